@@ -0,0 +1,14 @@
+data "mimirtool_alertmanager_route_test" "routing" {
+  config_yaml = mimirtool_alertmanager.demo.config_yaml
+  label_sets = [
+    { team = "a", severity = "critical" },
+    { team = "b" },
+  ]
+}
+
+check "critical_alerts_page" {
+  assert {
+    condition     = contains(data.mimirtool_alertmanager_route_test.routing.results[0].receivers, "pager")
+    error_message = "Critical alerts of team A are not sent to the pager."
+  }
+}
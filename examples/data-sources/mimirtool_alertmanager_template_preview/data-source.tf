@@ -0,0 +1,23 @@
+data "mimirtool_alertmanager_template_preview" "slack_title" {
+  templates_config_yaml = mimirtool_alertmanager.demo.templates_config_yaml
+  template              = "slack.title"
+  receiver              = "slack"
+  group_labels          = { alertname = "HighErrorRate" }
+  alerts = [
+    {
+      labels      = { alertname = "HighErrorRate", service = "api", severity = "critical" }
+      annotations = { summary = "Error rate above 5% on api" }
+    },
+    {
+      status      = "resolved"
+      labels      = { alertname = "HighErrorRate", service = "web", severity = "critical" }
+      annotations = { summary = "Error rate above 5% on web" }
+      starts_at   = "2024-01-02T03:04:05Z"
+      ends_at     = "2024-01-02T04:00:00Z"
+    },
+  ]
+}
+
+output "slack_title" {
+  value = data.mimirtool_alertmanager_template_preview.slack_title.rendered
+}
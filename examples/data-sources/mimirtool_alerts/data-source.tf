@@ -0,0 +1,17 @@
+data "mimirtool_alerts" "sev1" {
+  source   = "alertmanager"
+  matchers = ["severity=\"critical\""]
+  state    = "active"
+}
+
+check "no_sev1_firing" {
+  assert {
+    condition     = length(data.mimirtool_alerts.sev1.alerts) == 0
+    error_message = "Critical alerts are firing: ${join(", ", [for alert in data.mimirtool_alerts.sev1.alerts : alert.labels.alertname])}"
+  }
+}
+
+data "mimirtool_alerts" "pending" {
+  source = "ruler"
+  state  = "pending"
+}
@@ -0,0 +1,12 @@
+data "mimirtool_ruler_namespace" "platform" {
+  namespace = "platform"
+}
+
+# Names of the time series recorded by the platform team
+output "platform_recorded_series" {
+  value = flatten([
+    for group in data.mimirtool_ruler_namespace.platform.groups : [
+      for rule in group.rules : rule.name if rule.type == "record"
+    ]
+  ])
+}
@@ -0,0 +1,10 @@
+data "mimirtool_ruler_namespaces" "platform" {
+  namespace_regex = "^platform-"
+}
+
+# Import blocks for the namespaces of the platform team
+import {
+  for_each = { for ns in data.mimirtool_ruler_namespaces.platform.namespaces : ns.name => ns }
+  to       = mimirtool_ruler_namespace.platform[each.key]
+  id       = each.key
+}
@@ -0,0 +1,10 @@
+data "mimirtool_rules_status" "alerts" {
+  namespace = mimirtool_ruler_namespace.alerts.namespace
+}
+
+check "alerts_rules_healthy" {
+  assert {
+    condition     = data.mimirtool_rules_status.alerts.healthy
+    error_message = "Rules fail their evaluation: ${join(", ", flatten([for group in data.mimirtool_rules_status.alerts.groups : [for rule in group.rules : "${rule.name}: ${rule.last_error}" if rule.health == "err"]]))}"
+  }
+}
@@ -2,3 +2,17 @@ provider "mimirtool" {
   address   = "http://localhost:9009"
   tenant_id = "anonymous"
 }
+
+# The rules managed with this provider must follow this policy
+provider "mimirtool" {
+  alias   = "platform"
+  address = "http://localhost:9009"
+
+  rule_policy {
+    required_labels      = ["severity", "team"]
+    allowed_label_values = { severity = ["critical", "warning", "info"] }
+    required_annotations = ["runbook_url"]
+    record_name_pattern  = "[a-z0-9_]+:[a-z0-9_]+:[a-z0-9_]+"
+    forbidden_functions  = ["label_replace"]
+  }
+}
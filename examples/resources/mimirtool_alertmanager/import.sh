@@ -0,0 +1,3 @@
+terraform import mimirtool_alertmanager.demo alertmanager
+# Configuration of another tenant than the provider one
+terraform import mimirtool_alertmanager.demo tenant_id=tenant-a
@@ -19,3 +19,22 @@ EOT
 EOT
   }
 }
+
+# Receiver credentials kept out of the Terraform state (Terraform 1.11 or later)
+resource "mimirtool_alertmanager" "team_a" {
+  tenant_id   = "team-a"
+  config_yaml = <<EOT
+route:
+  receiver: slack
+receivers:
+  - name: slack
+    slack_configs:
+      - api_url: '<secret:slack_api_url>'
+        channel: '#alerts'
+EOT
+  secrets = {
+    slack_api_url = var.slack_api_url
+  }
+  # Bump to upload the configuration again after rotating a secret
+  secrets_version = 1
+}
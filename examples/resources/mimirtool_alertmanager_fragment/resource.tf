@@ -0,0 +1,37 @@
+resource "mimirtool_alertmanager_fragment" "team_a" {
+  name           = "team-a"
+  receivers_yaml = <<EOT
+- name: team-a
+  email_configs:
+    - to: 'team-a@example.org'
+EOT
+  routes_yaml    = <<EOT
+- receiver: team-a
+  matchers: ['team="a"']
+EOT
+  inhibit_rules_yaml = <<EOT
+- source_matchers: ['team="a"', 'severity="critical"']
+  target_matchers: ['team="a"', 'severity="warning"']
+  equal: ['alertname']
+EOT
+  templates = {
+    "team-a.tmpl" = <<EOT
+{{ define "team_a.subject" }}[{{ .Status | toUpper }}] {{ .CommonLabels.alertname }}{{ end }}
+EOT
+  }
+}
+
+resource "mimirtool_alertmanager" "demo" {
+  config_yaml = <<EOT
+global:
+  smtp_smarthost: 'localhost:25'
+  smtp_from: 'alertmanager@example.org'
+route:
+  receiver: default
+receivers:
+  - name: default
+EOT
+  fragments = [
+    mimirtool_alertmanager_fragment.team_a.fragment_yaml,
+  ]
+}
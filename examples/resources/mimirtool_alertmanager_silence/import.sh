@@ -0,0 +1,3 @@
+terraform import mimirtool_alertmanager_silence.maintenance 2a7a7e31-7c6b-4c3b-9c8e-5f4b8c1f2d3e
+# Silence managed in another tenant than the provider one
+terraform import mimirtool_alertmanager_silence.maintenance tenant_id=tenant-a:2a7a7e31-7c6b-4c3b-9c8e-5f4b8c1f2d3e
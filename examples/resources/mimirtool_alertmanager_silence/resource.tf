@@ -0,0 +1,17 @@
+resource "mimirtool_alertmanager_silence" "maintenance" {
+  matchers = [
+    { name = "cluster", value = "eu-west-1" },
+    { name = "alertname", value = "Node.*", is_regex = true },
+  ]
+  duration   = "2h"
+  comment    = "Planned maintenance of the eu-west-1 nodes"
+  created_by = "ops-team"
+}
+
+resource "mimirtool_alertmanager_silence" "upgrade" {
+  matchers   = [{ name = "service", value = "database" }]
+  starts_at  = "2030-01-02T22:00:00Z"
+  ends_at    = "2030-01-03T02:00:00Z"
+  comment    = "Database upgrade"
+  created_by = "dba-team"
+}
@@ -1 +1,3 @@
 terraform import mimirtool_ruler_namespace.demo demo
+# Namespace managed in another tenant than the provider one
+terraform import mimirtool_ruler_namespace.demo tenant_id=tenant-a:demo
@@ -12,3 +12,31 @@ groups:
     record: cluster_job:cortex_request_duration_seconds:50quantile
 EOT
 }
+
+# Rule groups can also be defined as blocks
+resource "mimirtool_ruler_namespace" "alerts" {
+  namespace = "alerts"
+
+  group {
+    name     = "mimir_alerts"
+    interval = "1m"
+
+    rule {
+      alert = "MimirRequestErrors"
+      expr  = "sum(rate(cortex_request_duration_seconds_count{status_code=~\"5..\"}[1m])) > 1"
+      for   = "15m"
+      labels = {
+        severity = "critical"
+      }
+      annotations = {
+        summary = "Mimir is returning errors."
+      }
+    }
+  }
+
+  # Fail the apply when a rule fails its first evaluation
+  wait_for_healthy_evaluation {
+    timeout  = "3m"
+    severity = "error"
+  }
+}
@@ -0,0 +1,5 @@
+terraform import mimirtool_ruler_rule_group.team_a shared/team_a
+# Rule group managed in another tenant than the provider one
+terraform import mimirtool_ruler_rule_group.team_a tenant_id=tenant-a:shared/team_a
+# Namespace and group names may contain slashes, the ID must match a single rule group
+terraform import mimirtool_ruler_rule_group.team_a teams/a/team_a
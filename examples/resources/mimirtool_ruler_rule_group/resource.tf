@@ -0,0 +1,22 @@
+resource "mimirtool_ruler_rule_group" "team_a" {
+  namespace = "shared"
+  name      = "team_a"
+  interval  = "1m"
+
+  rule {
+    record = "job:up:sum"
+    expr   = "sum by (job) (up)"
+  }
+
+  rule {
+    alert = "InstanceDown"
+    expr  = "up == 0"
+    for   = "5m"
+    labels = {
+      severity = "critical"
+    }
+    annotations = {
+      summary = "{{ $labels.instance }} is down."
+    }
+  }
+}
@@ -0,0 +1,421 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AlertmanagerFragmentResource{}
+var _ resource.ResourceWithModifyPlan = &AlertmanagerFragmentResource{}
+
+func NewAlertmanagerFragmentResource() resource.Resource {
+	return &AlertmanagerFragmentResource{}
+}
+
+// AlertmanagerFragmentResource holds the part of the Alertmanager configuration
+// owned by a team. It does not call Mimir: its fragment_yaml is merged into the
+// configuration by the mimirtool_alertmanager resource.
+type AlertmanagerFragmentResource struct{}
+
+// AlertmanagerFragmentResourceModel describes the resource data model.
+type AlertmanagerFragmentResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	ReceiversYAML    types.String `tfsdk:"receivers_yaml"`
+	RoutesYAML       types.String `tfsdk:"routes_yaml"`
+	InhibitRulesYAML types.String `tfsdk:"inhibit_rules_yaml"`
+	Templates        types.Map    `tfsdk:"templates"`
+	FragmentYAML     types.String `tfsdk:"fragment_yaml"`
+}
+
+// alertmanagerFragment is the content of fragment_yaml.
+type alertmanagerFragment struct {
+	Name         string                   `yaml:"name"`
+	Receivers    []map[string]interface{} `yaml:"receivers,omitempty"`
+	Routes       []interface{}            `yaml:"routes,omitempty"`
+	InhibitRules []interface{}            `yaml:"inhibit_rules,omitempty"`
+	Templates    map[string]string        `yaml:"templates,omitempty"`
+}
+
+func (r *AlertmanagerFragmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alertmanager_fragment"
+}
+
+func (r *AlertmanagerFragmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Defines the receivers, child routes, inhibit rules and templates owned by a team. Fragments are merged into the Alertmanager configuration by the `fragments` attribute of `mimirtool_alertmanager`. This resource does not call Grafana Mimir.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The name of the fragment.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the fragment, which must be unique among the fragments of a configuration. Fragments are merged in the order of their names.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"receivers_yaml": schema.StringAttribute{
+				MarkdownDescription: "The list of receivers of the fragment as YAML.",
+				Optional:            true,
+				Validators: []validator.String{
+					yamlSyntaxValidator{},
+				},
+			},
+			"routes_yaml": schema.StringAttribute{
+				MarkdownDescription: "The list of routes of the fragment as YAML, added as child routes of the root route after the routes of the configuration.",
+				Optional:            true,
+				Validators: []validator.String{
+					yamlSyntaxValidator{},
+				},
+			},
+			"inhibit_rules_yaml": schema.StringAttribute{
+				MarkdownDescription: "The list of inhibit rules of the fragment as YAML.",
+				Optional:            true,
+				Validators: []validator.String{
+					yamlSyntaxValidator{},
+				},
+			},
+			"templates": schema.MapAttribute{
+				MarkdownDescription: "A map of template names to template content, added to the templates of the configuration.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.Map{
+					alertmanagerTemplatesValidator{},
+				},
+			},
+			"fragment_yaml": schema.StringAttribute{
+				MarkdownDescription: "The fragment to merge into the Alertmanager configuration with the `fragments` attribute of `mimirtool_alertmanager`.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// ModifyPlan computes fragment_yaml during the plan so that the configuration
+// it is merged into is known and validated before apply.
+func (r *AlertmanagerFragmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to compute on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan AlertmanagerFragmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !plan.Name.IsUnknown() {
+		plan.ID = plan.Name
+	}
+	if !isFullyKnown(ctx, plan.Name) || !isFullyKnown(ctx, plan.ReceiversYAML) || !isFullyKnown(ctx, plan.RoutesYAML) ||
+		!isFullyKnown(ctx, plan.InhibitRulesYAML) || !isFullyKnown(ctx, plan.Templates) {
+		plan.FragmentYAML = types.StringUnknown()
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+
+	fragmentYAML, err := plan.fragmentYAML()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Alertmanager fragment",
+			fmt.Sprintf("Fragment %q is not valid: %s", plan.Name.ValueString(), err),
+		)
+		return
+	}
+	plan.FragmentYAML = types.StringValue(fragmentYAML)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *AlertmanagerFragmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AlertmanagerFragmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(plan.setFragmentYAML()...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *AlertmanagerFragmentResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+	// The fragment only exists in the Terraform state
+}
+
+func (r *AlertmanagerFragmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan AlertmanagerFragmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(plan.setFragmentYAML()...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *AlertmanagerFragmentResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	// The fragment only exists in the Terraform state
+}
+
+// setFragmentYAML sets the attributes computed from the fragment definition.
+func (m *AlertmanagerFragmentResourceModel) setFragmentYAML() (diags diag.Diagnostics) {
+	fragmentYAML, err := m.fragmentYAML()
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("name"),
+			"Invalid Alertmanager fragment",
+			fmt.Sprintf("Fragment %q is not valid: %s", m.Name.ValueString(), err),
+		)
+		return diags
+	}
+	m.ID = m.Name
+	m.FragmentYAML = types.StringValue(fragmentYAML)
+	return diags
+}
+
+// fragmentYAML returns the fragment as YAML.
+func (m AlertmanagerFragmentResourceModel) fragmentYAML() (string, error) {
+	fragment := alertmanagerFragment{
+		Name:      m.Name.ValueString(),
+		Templates: emptyToNil(mapStringFromTypesMap(m.Templates)),
+	}
+	if err := unmarshalFragmentList("receivers_yaml", m.ReceiversYAML, &fragment.Receivers); err != nil {
+		return "", err
+	}
+	if err := unmarshalFragmentList("routes_yaml", m.RoutesYAML, &fragment.Routes); err != nil {
+		return "", err
+	}
+	if err := unmarshalFragmentList("inhibit_rules_yaml", m.InhibitRulesYAML, &fragment.InhibitRules); err != nil {
+		return "", err
+	}
+	for i, receiver := range fragment.Receivers {
+		if name, _ := receiver["name"].(string); name == "" {
+			return "", fmt.Errorf("receivers_yaml: receiver %d has no name", i)
+		}
+	}
+
+	out, err := yaml.Marshal(fragment)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func unmarshalFragmentList(attribute string, value types.String, out interface{}) error {
+	if value.IsNull() {
+		return nil
+	}
+	if err := yaml.Unmarshal([]byte(value.ValueString()), out); err != nil {
+		return fmt.Errorf("%s must be a YAML list: %s", attribute, err)
+	}
+	return nil
+}
+
+// mergeAlertmanagerFragments merges the fragments into the configuration and
+// its templates. Fragments are merged in the order of their names, after the
+// content of the configuration. Receivers, templates and fragments sharing the
+// same name are reported as conflicts.
+func mergeAlertmanagerFragments(configYAML string, templates map[string]string, fragmentsYAML []string) (string, map[string]string, error) {
+	if len(fragmentsYAML) == 0 {
+		return configYAML, templates, nil
+	}
+
+	fragments := make([]alertmanagerFragment, 0, len(fragmentsYAML))
+	for i, fragmentYAML := range fragmentsYAML {
+		var fragment alertmanagerFragment
+		if err := yaml.Unmarshal([]byte(fragmentYAML), &fragment); err != nil {
+			return "", nil, fmt.Errorf("fragment %d is not valid: %s", i, err)
+		}
+		if fragment.Name == "" {
+			return "", nil, fmt.Errorf("fragment %d has no name", i)
+		}
+		fragments = append(fragments, fragment)
+	}
+	sort.SliceStable(fragments, func(i, j int) bool {
+		return fragments[i].Name < fragments[j].Name
+	})
+	for i := 1; i < len(fragments); i++ {
+		if fragments[i].Name == fragments[i-1].Name {
+			return "", nil, fmt.Errorf("fragment %q is defined more than once", fragments[i].Name)
+		}
+	}
+
+	var cfg map[string]interface{}
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		return "", nil, err
+	}
+	if cfg == nil {
+		cfg = map[string]interface{}{}
+	}
+
+	receiverOwners := map[string]string{}
+	receivers, _ := cfg["receivers"].([]interface{})
+	for _, receiver := range receivers {
+		if receiver, ok := receiver.(map[string]interface{}); ok {
+			if name, ok := receiver["name"].(string); ok {
+				receiverOwners[name] = "the configuration"
+			}
+		}
+	}
+	templateOwners := map[string]string{}
+	mergedTemplates := make(map[string]string, len(templates))
+	for name, content := range templates {
+		templateOwners[name] = "templates_config_yaml"
+		mergedTemplates[name] = content
+	}
+	templateGlobs, _ := cfg["templates"].([]interface{})
+	routes, inhibitRules := []interface{}{}, []interface{}{}
+
+	for _, fragment := range fragments {
+		owner := fmt.Sprintf("fragment %q", fragment.Name)
+		for _, receiver := range fragment.Receivers {
+			name, _ := receiver["name"].(string)
+			if other, ok := receiverOwners[name]; ok {
+				return "", nil, fmt.Errorf("receiver %q of %s is already defined by %s", name, owner, other)
+			}
+			receiverOwners[name] = owner
+			receivers = append(receivers, receiver)
+		}
+
+		templateNames := make([]string, 0, len(fragment.Templates))
+		for name := range fragment.Templates {
+			templateNames = append(templateNames, name)
+		}
+		sort.Strings(templateNames)
+		for _, name := range templateNames {
+			if other, ok := templateOwners[name]; ok {
+				return "", nil, fmt.Errorf("template %q of %s is already defined by %s", name, owner, other)
+			}
+			templateOwners[name] = owner
+			mergedTemplates[name] = fragment.Templates[name]
+			templateGlobs = append(templateGlobs, name)
+		}
+
+		routes = append(routes, fragment.Routes...)
+		inhibitRules = append(inhibitRules, fragment.InhibitRules...)
+	}
+
+	if len(receivers) > 0 {
+		cfg["receivers"] = receivers
+	}
+	if len(templateGlobs) > 0 {
+		cfg["templates"] = templateGlobs
+	}
+	if len(routes) > 0 {
+		route, ok := cfg["route"].(map[string]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("the configuration has no root route to add the routes of the fragments to")
+		}
+		childRoutes, _ := route["routes"].([]interface{})
+		route["routes"] = append(childRoutes, routes...)
+	}
+	if len(inhibitRules) > 0 {
+		existing, _ := cfg["inhibit_rules"].([]interface{})
+		cfg["inhibit_rules"] = append(existing, inhibitRules...)
+	}
+
+	merged, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(merged), mergedTemplates, nil
+}
+
+// removeAlertmanagerFragments removes from the configuration the receivers,
+// routes, inhibit rules and templates merged from the fragments, so that the
+// parts of the configuration owned by the fragments are not reported as part of
+// the configuration. Parts changed since they were merged are kept.
+func removeAlertmanagerFragments(configYAML string, fragmentsYAML []string) (string, error) {
+	if len(fragmentsYAML) == 0 {
+		return configYAML, nil
+	}
+	var cfg map[string]interface{}
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		return "", err
+	}
+	if cfg == nil {
+		return configYAML, nil
+	}
+
+	receiverNames := map[string]bool{}
+	templateNames := map[string]bool{}
+	var routes, inhibitRules []interface{}
+	for i, fragmentYAML := range fragmentsYAML {
+		var fragment alertmanagerFragment
+		if err := yaml.Unmarshal([]byte(fragmentYAML), &fragment); err != nil {
+			return "", fmt.Errorf("fragment %d is not valid: %s", i, err)
+		}
+		for _, receiver := range fragment.Receivers {
+			if name, ok := receiver["name"].(string); ok {
+				receiverNames[name] = true
+			}
+		}
+		for name := range fragment.Templates {
+			templateNames[name] = true
+		}
+		routes = append(routes, fragment.Routes...)
+		inhibitRules = append(inhibitRules, fragment.InhibitRules...)
+	}
+
+	setOrDelete := func(m map[string]interface{}, key string, values []interface{}) {
+		if len(values) == 0 {
+			delete(m, key)
+			return
+		}
+		m[key] = values
+	}
+	if receivers, ok := cfg["receivers"].([]interface{}); ok {
+		setOrDelete(cfg, "receivers", slices.DeleteFunc(receivers, func(receiver interface{}) bool {
+			receiverMap, _ := receiver.(map[string]interface{})
+			name, _ := receiverMap["name"].(string)
+			return receiverNames[name]
+		}))
+	}
+	if globs, ok := cfg["templates"].([]interface{}); ok {
+		setOrDelete(cfg, "templates", slices.DeleteFunc(globs, func(glob interface{}) bool {
+			name, _ := glob.(string)
+			return templateNames[name]
+		}))
+	}
+	if route, ok := cfg["route"].(map[string]interface{}); ok {
+		if childRoutes, ok := route["routes"].([]interface{}); ok {
+			setOrDelete(route, "routes", removeFirstEqual(childRoutes, routes))
+		}
+	}
+	if existing, ok := cfg["inhibit_rules"].([]interface{}); ok {
+		setOrDelete(cfg, "inhibit_rules", removeFirstEqual(existing, inhibitRules))
+	}
+
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// removeFirstEqual removes from the values one value equal to each of the
+// removed values.
+func removeFirstEqual(values, removed []interface{}) []interface{} {
+	for _, r := range removed {
+		if i := slices.IndexFunc(values, func(v interface{}) bool { return reflect.DeepEqual(v, r) }); i >= 0 {
+			values = slices.Delete(values, i, i+1)
+		}
+	}
+	return values
+}
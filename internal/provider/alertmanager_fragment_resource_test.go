@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAccResourceAlertmanagerFragment(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerFragment,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_fragment.team_a", "id", "team-a"),
+					resource.TestCheckResourceAttrSet("mimirtool_alertmanager_fragment.team_a", "fragment_yaml"),
+					resource.TestCheckNoResourceAttr("mimirtool_alertmanager.demo", "templates_config_yaml.%"),
+					testAccCheckAlertmanagerConfig(t, "- team-a.tmpl"),
+					testAccCheckAlertmanagerConfig(t, "receiver: team-a"),
+					testAccCheckAlertmanagerConfig(t, "receiver: team-b"),
+				),
+			},
+			{
+				Config:      strings.Replace(testAccResourceAlertmanagerFragment, "- name: team-b", "- name: team-a", 1),
+				ExpectError: regexp.MustCompile(`receiver "team-a" of\s+fragment "team-b" is already defined by fragment "team-a"`),
+			},
+			{
+				Config: testAccResourceAlertmanagerFragment,
+			},
+			{
+				// The root route may use a receiver defined only by a fragment
+				Config: strings.Replace(testAccResourceAlertmanagerFragment, "  receiver: default\n", "  receiver: team-a\n", 1),
+				Check:  testAccCheckAlertmanagerConfig(t, "receiver: team-a"),
+			},
+		},
+	})
+}
+
+func TestMergeAlertmanagerFragments(t *testing.T) {
+	const configYAML = `route:
+  receiver: default
+  routes:
+    - receiver: default
+      matchers: ['team="ops"']
+receivers:
+  - name: default
+templates: ['default.tmpl']
+`
+	teamA := `name: team-a
+receivers:
+  - name: team-a
+routes:
+  - receiver: team-a
+    matchers: ['team="a"']
+templates:
+  team-a.tmpl: '{{ define "team_a" }}A{{ end }}'
+`
+	teamB := `name: team-b
+receivers:
+  - name: team-b
+routes:
+  - receiver: team-b
+inhibit_rules:
+  - source_matchers: ['severity="critical"']
+    target_matchers: ['severity="warning"']
+`
+
+	merged, templates, err := mergeAlertmanagerFragments(configYAML, map[string]string{"default.tmpl": ""}, []string{teamB, teamA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg struct {
+		Route struct {
+			Routes []struct {
+				Receiver string `yaml:"receiver"`
+			} `yaml:"routes"`
+		} `yaml:"route"`
+		Receivers []struct {
+			Name string `yaml:"name"`
+		} `yaml:"receivers"`
+		InhibitRules []interface{} `yaml:"inhibit_rules"`
+		Templates    []string      `yaml:"templates"`
+	}
+	if err := yaml.Unmarshal([]byte(merged), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	var routes, receivers []string
+	for _, route := range cfg.Route.Routes {
+		routes = append(routes, route.Receiver)
+	}
+	for _, receiver := range cfg.Receivers {
+		receivers = append(receivers, receiver.Name)
+	}
+	// Fragments are merged in the order of their names, after the configuration
+	if got := strings.Join(routes, ","); got != "default,team-a,team-b" {
+		t.Errorf("unexpected routes order: %s", got)
+	}
+	if got := strings.Join(receivers, ","); got != "default,team-a,team-b" {
+		t.Errorf("unexpected receivers order: %s", got)
+	}
+	if len(cfg.InhibitRules) != 1 {
+		t.Errorf("expected 1 inhibit rule, got %d", len(cfg.InhibitRules))
+	}
+	if got := strings.Join(cfg.Templates, ","); got != "default.tmpl,team-a.tmpl" {
+		t.Errorf("unexpected templates: %s", got)
+	}
+	if _, ok := templates["team-a.tmpl"]; !ok || len(templates) != 2 {
+		t.Errorf("expected the templates of the fragments to be merged, got %v", templates)
+	}
+	if _, err := loadAlertmanagerConfig(merged); err != nil {
+		t.Errorf("invalid merged configuration: %s", err)
+	}
+
+	// Merging is deterministic
+	again, _, err := mergeAlertmanagerFragments(configYAML, map[string]string{"default.tmpl": ""}, []string{teamA, teamB})
+	if err != nil || again != merged {
+		t.Errorf("expected the same configuration whatever the order of the fragments, got %q (%v)", again, err)
+	}
+
+	// Removing the fragments gives the configuration back, except for the
+	// parts changed since they were merged
+	removed, err := removeAlertmanagerFragments(merged, []string{teamA, teamB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !alertmanagerConfigsEqual(configYAML, removed) {
+		t.Errorf("expected the parts of the fragments to be removed, got:\n%s", removed)
+	}
+	drifted := strings.Replace(merged, "team=\"a\"", "team=\"c\"", 1)
+	if removed, err = removeAlertmanagerFragments(drifted, []string{teamA, teamB}); err != nil || !strings.Contains(removed, "team=\"c\"") || strings.Contains(removed, "team-b") {
+		t.Errorf("expected only the changed route to be kept, got %q (%v)", removed, err)
+	}
+
+	for _, tc := range []struct {
+		fragments []string
+		err       string
+	}{
+		{[]string{teamA, teamA}, `fragment "team-a" is defined more than once`},
+		{[]string{strings.Replace(teamA, "- name: team-a", "- name: default", 1)}, `receiver "default" of fragment "team-a" is already defined by the configuration`},
+		{[]string{strings.Replace(teamA, "team-a.tmpl", "default.tmpl", 1)}, `template "default.tmpl" of fragment "team-a" is already defined by templates_config_yaml`},
+		{[]string{teamB, strings.Replace(teamA, "- name: team-a", "- name: team-b", 1)}, `receiver "team-b" of fragment "team-b" is already defined by fragment "team-a"`},
+	} {
+		if _, _, err := mergeAlertmanagerFragments(configYAML, map[string]string{"default.tmpl": ""}, tc.fragments); err == nil || err.Error() != tc.err {
+			t.Errorf("expected error %q, got %v", tc.err, err)
+		}
+	}
+}
+
+const testAccResourceAlertmanagerFragment = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_alertmanager_fragment" "team_a" {
+  name           = "team-a"
+  receivers_yaml = <<YAML
+- name: team-a
+  email_configs:
+    - to: 'team-a@example.org'
+YAML
+  routes_yaml    = <<YAML
+- receiver: team-a
+  matchers: ['team="a"']
+YAML
+  templates = {
+    "team-a.tmpl" = "{{ define \"team_a\" }}Team A{{ end }}"
+  }
+}
+
+resource "mimirtool_alertmanager_fragment" "team_b" {
+  name           = "team-b"
+  receivers_yaml = <<YAML
+- name: team-b
+  email_configs:
+    - to: 'team-b@example.org'
+YAML
+  routes_yaml    = <<YAML
+- receiver: team-b
+  matchers: ['team="b"']
+YAML
+  inhibit_rules_yaml = <<YAML
+- source_matchers: ['team="b"', 'severity="critical"']
+  target_matchers: ['team="b"', 'severity="warning"']
+  equal: ['alertname']
+YAML
+}
+
+resource "mimirtool_alertmanager" "demo" {
+  config_yaml = <<YAML
+global:
+  smtp_smarthost: 'localhost:25'
+  smtp_from: 'alertmanager@example.org'
+route:
+  receiver: default
+receivers:
+  - name: default
+YAML
+  fragments = [
+    mimirtool_alertmanager_fragment.team_b.fragment_yaml,
+    mimirtool_alertmanager_fragment.team_a.fragment_yaml,
+  ]
+}
+`
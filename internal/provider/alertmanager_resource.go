@@ -7,10 +7,16 @@ package provider
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"errors"
 
 	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -19,12 +25,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	amconfig "github.com/prometheus/alertmanager/config"
+	commoncfg "github.com/prometheus/common/config"
+	"gopkg.in/yaml.v3"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
-	_ resource.Resource                = &AlertmanagerResource{}
-	_ resource.ResourceWithImportState = &AlertmanagerResource{}
+	_ resource.Resource                   = &AlertmanagerResource{}
+	_ resource.ResourceWithImportState    = &AlertmanagerResource{}
+	_ resource.ResourceWithValidateConfig = &AlertmanagerResource{}
 )
 
 func NewAlertmanagerResource() resource.Resource {
@@ -46,23 +56,55 @@ func (r *AlertmanagerResource) Schema(_ context.Context, _ resource.SchemaReques
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "ID for the Alertmanager resource (always 'alertmanager'). This is a singleton resource per tenant.",
+				MarkdownDescription: "ID for the Alertmanager resource ('alertmanager', or the `tenant_id` when set). This is a singleton resource per tenant.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
 			"config_yaml": schema.StringAttribute{
-				MarkdownDescription: "The Alertmanager configuration to load in Grafana Mimir as YAML. This should be a valid Alertmanager YAML config.",
+				MarkdownDescription: "The Alertmanager configuration to load in Grafana Mimir as YAML. Once merged with the `fragments`, it is validated with the Alertmanager configuration loader: receiver references, route tree, inhibit rules, matchers, time intervals and durations. Secrets redacted by Grafana Mimir, such as `smtp_auth_password`, `api_key` or webhook URLs, are considered equal to the configured ones.",
 				Required:            true,
+				Sensitive:           true,
 				Validators: []validator.String{
 					yamlSyntaxValidator{},
 				},
 			},
+			"remote_config_yaml": schema.StringAttribute{
+				MarkdownDescription: "The Alertmanager configuration stored in Grafana Mimir as normalized YAML. Its secrets, and the values substituted for the `<secret:NAME>` placeholders, are redacted.",
+				Computed:            true,
+				Sensitive:           true,
+			},
 			"templates_config_yaml": schema.MapAttribute{
-				MarkdownDescription: "A map of template names to template YAML content to load along with the Alertmanager configuration.",
+				MarkdownDescription: "A map of template names to template YAML content to load along with the Alertmanager configuration. Templates are parsed with the Alertmanager template engine, and every entry of the configuration's `templates` must match one of the names.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.Map{
+					alertmanagerTemplatesValidator{},
+				},
+			},
+			"fragments": schema.ListAttribute{
+				MarkdownDescription: "The `fragment_yaml` of `mimirtool_alertmanager_fragment` resources to merge into the configuration. Their receivers, inhibit rules and templates are added to the ones of the configuration, and their routes are added as child routes of the root route, in the order of the fragment names. Receivers or templates defined more than once are reported as errors.",
 				ElementType:         types.StringType,
 				Optional:            true,
 			},
+			"secrets": schema.MapAttribute{
+				MarkdownDescription: "Secrets substituted into the `<secret:NAME>` placeholders of `config_yaml` when the configuration is uploaded to Grafana Mimir. This attribute is write-only and never stored in the Terraform state: change `secrets_version` to upload rotated secrets. Requires Terraform 1.11 or later.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Sensitive:           true,
+				WriteOnly:           true,
+			},
+			"secrets_version": schema.Int64Attribute{
+				MarkdownDescription: "Version of the `secrets`. Changing it uploads the configuration again with the current secrets.",
+				Optional:            true,
+			},
+			"tenant_id": schema.StringAttribute{
+				MarkdownDescription: "Tenant ID to manage the Alertmanager configuration of. Overrides the provider `tenant_id`.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
@@ -82,10 +124,415 @@ func (r *AlertmanagerResource) Configure(_ context.Context, req resource.Configu
 	r.client = client
 }
 
+// ValidateConfig ensures the configuration merged with the fragments is valid,
+// and that the templates and the secrets it references are provided.
+func (r *AlertmanagerResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config AlertmanagerResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.ConfigYAML.IsNull() || config.ConfigYAML.IsUnknown() || config.ConfigYAML.ValueString() == "" || !isFullyKnown(ctx, config.Fragments) {
+		return
+	}
+	// Invalid YAML is reported by the config_yaml validators
+	var temp interface{}
+	if err := yaml.Unmarshal([]byte(config.ConfigYAML.ValueString()), &temp); err != nil {
+		return
+	}
+
+	fullConfig, templates, err := config.mergedConfig(ctx)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("fragments"),
+			"Conflicting Alertmanager fragments",
+			fmt.Sprintf("The fragments can't be merged into the configuration: %s", err),
+		)
+		return
+	}
+
+	if isFullyKnown(ctx, config.Secrets) {
+		if missing := missingAlertmanagerSecrets(fullConfig, mapStringFromTypesMap(config.Secrets)); len(missing) > 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("secrets"),
+				"Missing Alertmanager secret",
+				fmt.Sprintf("The configuration references secrets which are not provided by secrets: %s", strings.Join(missing, ", ")),
+			)
+		}
+	}
+
+	// The configuration is validated once merged with the fragments, as its
+	// routes may reference the receivers of the fragments
+	cfg, err := loadAlertmanagerConfig(fullConfig)
+	if err != nil {
+		detail := fmt.Sprintf("Alertmanager configuration is not valid: %s", err)
+		if !config.Fragments.IsNull() {
+			detail = fmt.Sprintf("The configuration merged with the fragments is not valid: %s", err)
+		}
+		resp.Diagnostics.AddAttributeError(path.Root("config_yaml"), "Invalid Alertmanager configuration", detail)
+		return
+	}
+	if !isFullyKnown(ctx, config.TemplatesConfigYAML) {
+		return
+	}
+	if missing := missingAlertmanagerTemplates(cfg.Templates, templates); len(missing) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("templates_config_yaml"),
+			"Missing Alertmanager template",
+			fmt.Sprintf("The configuration references templates which are not provided by templates_config_yaml: %s", strings.Join(missing, ", ")),
+		)
+	}
+}
+
+// mergedConfig returns the configuration and the templates merged with the fragments.
+func (m AlertmanagerResourceModel) mergedConfig(ctx context.Context) (string, map[string]string, error) {
+	var fragments []string
+	if diags := m.Fragments.ElementsAs(ctx, &fragments, false); diags.HasError() {
+		return "", nil, fmt.Errorf("invalid fragments: %v", diags)
+	}
+	return mergeAlertmanagerFragments(m.ConfigYAML.ValueString(), mapStringFromTypesMap(m.TemplatesConfigYAML), fragments)
+}
+
+// fetchAlertmanagerConfig reads the Alertmanager configuration back from Mimir
+// after it was written.
+func fetchAlertmanagerConfig(ctx context.Context, client mimirClientInterface, op string, diagnostics *diag.Diagnostics) (string, bool) {
+	alertmanagerConfig, _, err := client.GetAlertmanagerConfig(ctx)
+	if err != nil {
+		diagnostics.AddError(
+			fmt.Sprintf("Error reading Alertmanager config after %s", op),
+			fmt.Sprintf("Could not read Alertmanager config: %s", err.Error()),
+		)
+		return "", false
+	}
+	return alertmanagerConfig, true
+}
+
+// normalizeAlertmanagerConfigYAML returns the configuration re-serialized with
+// sorted keys, without comments and with its secrets redacted, or the
+// configuration itself when it can't be parsed. Besides the secret fields, the
+// values substituted for the secret placeholders of the desired configuration
+// are redacted, whatever their field.
+func normalizeAlertmanagerConfigYAML(configYAML, desiredYAML string) string {
+	var cfg interface{}
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		return configYAML
+	}
+	redactAlertmanagerSecrets(cfg, alertmanagerConfigType)
+	if alertmanagerSecretPlaceholder.MatchString(desiredYAML) {
+		var desired interface{}
+		if err := yaml.Unmarshal([]byte(desiredYAML), &desired); err == nil {
+			redactAlertmanagerPlaceholders(cfg, desired)
+		}
+	}
+	normalized, err := yaml.Marshal(cfg)
+	if err != nil {
+		return configYAML
+	}
+	return string(normalized)
+}
+
+// alertmanagerConfigsEqual reports whether the desired configuration and the
+// one read from Mimir hold the same values, regardless of the formatting, key
+// order and comments. Secrets redacted in the remote configuration match any
+// desired value.
+func alertmanagerConfigsEqual(desired, remote string) bool {
+	if desired == remote {
+		return true
+	}
+	var desiredCfg, remoteCfg interface{}
+	if yaml.Unmarshal([]byte(desired), &desiredCfg) != nil || yaml.Unmarshal([]byte(remote), &remoteCfg) != nil {
+		return false
+	}
+	return alertmanagerValuesEqual(desiredCfg, remoteCfg, alertmanagerConfigType)
+}
+
+// redactAlertmanagerSecrets replaces the secrets of the configuration, whose
+// Go type is t, with the secret mask.
+func redactAlertmanagerSecrets(cfg interface{}, t reflect.Type) {
+	switch cfg := cfg.(type) {
+	case map[string]interface{}:
+		for k, v := range cfg {
+			fieldType := alertmanagerFieldType(t, k)
+			if _, ok := v.(string); ok && isAlertmanagerSecretType(fieldType) {
+				cfg[k] = alertmanagerSecretMask
+				continue
+			}
+			redactAlertmanagerSecrets(v, fieldType)
+		}
+	case []interface{}:
+		elemType := alertmanagerElemType(t)
+		for i, v := range cfg {
+			if _, ok := v.(string); ok && isAlertmanagerSecretType(elemType) {
+				cfg[i] = alertmanagerSecretMask
+				continue
+			}
+			redactAlertmanagerSecrets(v, elemType)
+		}
+	}
+}
+
+// redactAlertmanagerPlaceholders replaces the values of the configuration
+// found where the desired configuration holds secret placeholders with the
+// secret mask.
+func redactAlertmanagerPlaceholders(cfg, desired interface{}) {
+	switch cfg := cfg.(type) {
+	case map[string]interface{}:
+		desired, _ := desired.(map[string]interface{})
+		for k, v := range cfg {
+			if isAlertmanagerPlaceholderValue(desired[k]) {
+				cfg[k] = alertmanagerSecretMask
+				continue
+			}
+			redactAlertmanagerPlaceholders(v, desired[k])
+		}
+	case []interface{}:
+		desired, _ := desired.([]interface{})
+		for i, v := range cfg {
+			if i >= len(desired) {
+				break
+			}
+			if isAlertmanagerPlaceholderValue(desired[i]) {
+				cfg[i] = alertmanagerSecretMask
+				continue
+			}
+			redactAlertmanagerPlaceholders(v, desired[i])
+		}
+	}
+}
+
+func isAlertmanagerPlaceholderValue(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && alertmanagerSecretPlaceholder.MatchString(s)
+}
+
+// alertmanagerValuesEqual compares the values of the configurations whose Go
+// type is t.
+func alertmanagerValuesEqual(desired, remote interface{}, t reflect.Type) bool {
+	switch remote := remote.(type) {
+	case map[string]interface{}:
+		desired, ok := desired.(map[string]interface{})
+		if !ok || len(desired) != len(remote) {
+			return false
+		}
+		for k, v := range remote {
+			desiredValue, ok := desired[k]
+			if !ok || !alertmanagerValuesEqual(desiredValue, v, alertmanagerFieldType(t, k)) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		desired, ok := desired.([]interface{})
+		if !ok || len(desired) != len(remote) {
+			return false
+		}
+		elemType := alertmanagerElemType(t)
+		for i := range remote {
+			if !alertmanagerValuesEqual(desired[i], remote[i], elemType) {
+				return false
+			}
+		}
+		return true
+	case string:
+		desired, ok := desired.(string)
+		if !ok {
+			return false
+		}
+		if remote == alertmanagerSecretMask && isAlertmanagerSecretType(t) {
+			return true
+		}
+		if alertmanagerSecretPlaceholder.MatchString(desired) {
+			return remote == alertmanagerSecretMask || secretPlaceholderPattern(desired).MatchString(remote)
+		}
+		return desired == remote
+	}
+	return reflect.DeepEqual(desired, remote)
+}
+
+// alertmanagerSecretMask is the value secrets are replaced with when the
+// Alertmanager configuration is marshalled.
+const alertmanagerSecretMask = "<secret>"
+
+// alertmanagerConfigType is the Go type of the Alertmanager configuration,
+// which tells where its secrets are, such as `smtp_auth_password`, the
+// `api_key` of OpsGenie or the webhook `url`.
+var alertmanagerConfigType = reflect.TypeOf(amconfig.Config{})
+
+var secretTypes = map[reflect.Type]bool{
+	reflect.TypeOf(amconfig.Secret("")):  true,
+	reflect.TypeOf(amconfig.SecretURL{}): true,
+	reflect.TypeOf(commoncfg.Secret("")): true,
+}
+
+// isAlertmanagerSecretType reports whether values of the type, or the elements
+// of a list of that type, are secrets.
+func isAlertmanagerSecretType(t reflect.Type) bool {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	return t != nil && secretTypes[t]
+}
+
+// alertmanagerFieldType returns the type of the value found under the YAML key
+// in a value of the type, looking into the inlined fields of structs, or nil
+// when unknown.
+func alertmanagerFieldType(t reflect.Type, key string) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == nil:
+		return nil
+	case t.Kind() == reflect.Map:
+		return t.Elem()
+	case t.Kind() != reflect.Struct:
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if options == "inline" || strings.HasPrefix(options, "inline,") {
+			if fieldType := alertmanagerFieldType(field.Type, key); fieldType != nil {
+				return fieldType
+			}
+			continue
+		}
+		if name == key {
+			return field.Type
+		}
+	}
+	return nil
+}
+
+// alertmanagerElemType returns the type of the elements of a list of the type,
+// or nil when unknown.
+func alertmanagerElemType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Slice {
+		return nil
+	}
+	return t.Elem()
+}
+
+// alertmanagerSecretPlaceholder matches the placeholders of the configuration
+// substituted with the secrets, such as `<secret:slack_api_url>`.
+var alertmanagerSecretPlaceholder = regexp.MustCompile(`<secret:([A-Za-z0-9_.-]+)>`)
+
+// loadAlertmanagerConfig loads the configuration with its secret placeholders
+// replaced with the secret mask, which is accepted by every secret field.
+func loadAlertmanagerConfig(configYAML string) (*amconfig.Config, error) {
+	return amconfig.Load(alertmanagerSecretPlaceholder.ReplaceAllLiteralString(configYAML, alertmanagerSecretMask))
+}
+
+// secretPlaceholderPattern returns a regular expression matching the value
+// once its secret placeholders are substituted.
+func secretPlaceholderPattern(value string) *regexp.Regexp {
+	var pattern strings.Builder
+	pattern.WriteString("(?s)^")
+	last := 0
+	for _, loc := range alertmanagerSecretPlaceholder.FindAllStringIndex(value, -1) {
+		pattern.WriteString(regexp.QuoteMeta(value[last:loc[0]]))
+		pattern.WriteString(".*")
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(value[last:]))
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String())
+}
+
+// missingAlertmanagerSecrets returns the quoted names of the secret
+// placeholders of the configuration which are not provided.
+func missingAlertmanagerSecrets(configYAML string, secrets map[string]string) []string {
+	var missing []string
+	seen := map[string]bool{}
+	for _, match := range alertmanagerSecretPlaceholder.FindAllStringSubmatch(configYAML, -1) {
+		name := match[1]
+		if _, ok := secrets[name]; ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		missing = append(missing, strconv.Quote(name))
+	}
+	return missing
+}
+
+// substituteAlertmanagerSecrets replaces the secret placeholders found in the
+// values of the configuration with the secrets. The values are substituted in
+// the parsed YAML document so that secrets don't need to be escaped.
+func substituteAlertmanagerSecrets(configYAML string, secrets map[string]string) (string, error) {
+	if !alertmanagerSecretPlaceholder.MatchString(configYAML) {
+		return configYAML, nil
+	}
+	if missing := missingAlertmanagerSecrets(configYAML, secrets); len(missing) > 0 {
+		return "", fmt.Errorf("the configuration references secrets which are not provided: %s", strings.Join(missing, ", "))
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(configYAML), &document); err != nil {
+		return "", err
+	}
+	substituteSecretsInNode(&document, secrets)
+	substituted, err := yaml.Marshal(&document)
+	if err != nil {
+		return "", err
+	}
+	return string(substituted), nil
+}
+
+func substituteSecretsInNode(node *yaml.Node, secrets map[string]string) {
+	if node.Kind == yaml.ScalarNode {
+		node.Value = alertmanagerSecretPlaceholder.ReplaceAllStringFunc(node.Value, func(placeholder string) string {
+			return secrets[alertmanagerSecretPlaceholder.FindStringSubmatch(placeholder)[1]]
+		})
+		return
+	}
+	for _, child := range node.Content {
+		substituteSecretsInNode(child, secrets)
+	}
+}
+
+// missingAlertmanagerTemplates returns the template globs of the configuration
+// which do not match any of the provided templates.
+func missingAlertmanagerTemplates(globs []string, templates map[string]string) []string {
+	var missing []string
+	for _, glob := range globs {
+		found := false
+		for name := range templates {
+			if matched, _ := filepath.Match(glob, name); matched {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, fmt.Sprintf("%q", glob))
+		}
+	}
+	return missing
+}
+
 type AlertmanagerResourceModel struct {
 	ID                  types.String `tfsdk:"id"`
 	ConfigYAML          types.String `tfsdk:"config_yaml"`
+	RemoteConfigYAML    types.String `tfsdk:"remote_config_yaml"`
 	TemplatesConfigYAML types.Map    `tfsdk:"templates_config_yaml"`
+	Fragments           types.List   `tfsdk:"fragments"`
+	Secrets             types.Map    `tfsdk:"secrets"`
+	SecretsVersion      types.Int64  `tfsdk:"secrets_version"`
+	TenantID            types.String `tfsdk:"tenant_id"`
+}
+
+// alertmanagerID returns the resource ID: 'alertmanager', or the tenant ID when
+// the resource overrides the provider one.
+func alertmanagerID(tenantID types.String) types.String {
+	if tenantID.ValueString() == "" {
+		return types.StringValue("alertmanager")
+	}
+	return tenantID
 }
 
 func (r *AlertmanagerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -95,10 +542,32 @@ func (r *AlertmanagerResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	alertmanagerConfig := plan.ConfigYAML.ValueString()
-	templates := mapStringFromTypesMap(plan.TemplatesConfigYAML)
+	// Write-only secrets are only available in the configuration
+	var secrets types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("secrets"), &secrets)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	fullConfig, templates, err := plan.mergedConfig(ctx)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("fragments"),
+			"Conflicting Alertmanager fragments",
+			fmt.Sprintf("The fragments can't be merged into the configuration: %s", err),
+		)
+		return
+	}
+	alertmanagerConfig, err := substituteAlertmanagerSecrets(fullConfig, mapStringFromTypesMap(secrets))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("secrets"),
+			"Error substituting Alertmanager secrets",
+			err.Error(),
+		)
+		return
+	}
 
-	err := r.client.CreateAlertmanagerConfig(ctx, alertmanagerConfig, templates)
+	err = tenantClient(r.client, plan.TenantID).CreateAlertmanagerConfig(ctx, alertmanagerConfig, templates)
 	if err != nil {
 		tflog.Error(ctx, "Failed to create Alertmanager config via POST", map[string]interface{}{"error": err})
 		resp.Diagnostics.AddError(
@@ -108,7 +577,15 @@ func (r *AlertmanagerResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	plan.ID = types.StringValue("alertmanager")
+	plan.ID = alertmanagerID(plan.TenantID)
+
+	// Always fetch the canonical configuration from the backend and store it in state
+	remoteConfig, ok := fetchAlertmanagerConfig(ctx, tenantClient(r.client, plan.TenantID), "CREATE", &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	plan.RemoteConfigYAML = types.StringValue(normalizeAlertmanagerConfigYAML(remoteConfig, fullConfig))
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -119,7 +596,7 @@ func (r *AlertmanagerResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	alertmanagerConfig, templates, err := r.client.GetAlertmanagerConfig(ctx)
+	alertmanagerConfig, templates, err := tenantClient(r.client, state.TenantID).GetAlertmanagerConfig(ctx)
 	if err != nil {
 		if errors.Is(err, client.ErrResourceNotFound) {
 			tflog.Info(ctx, "No alertmanager config found in backend; removing from state")
@@ -134,8 +611,52 @@ func (r *AlertmanagerResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	state.ConfigYAML = types.StringValue(alertmanagerConfig)
-	state.TemplatesConfigYAML = typeMapFromMapString(templates)
+	fullConfig, fullTemplates, err := state.mergedConfig(ctx)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("fragments"),
+			"Conflicting Alertmanager fragments",
+			fmt.Sprintf("The fragments can't be merged into the configuration: %s", err),
+		)
+		return
+	}
+	state.RemoteConfigYAML = types.StringValue(normalizeAlertmanagerConfigYAML(alertmanagerConfig, fullConfig))
+
+	// Keep the user's text when the configuration stored in Mimir is equivalent,
+	// so that re-serialization by Mimir does not show up as a change.
+	if state.ConfigYAML.IsNull() || !alertmanagerConfigsEqual(fullConfig, alertmanagerConfig) {
+		ownConfig := alertmanagerConfig
+		if alertmanagerSecretPlaceholder.MatchString(fullConfig) {
+			// Don't store the substituted secrets
+			ownConfig = state.RemoteConfigYAML.ValueString()
+		}
+		// Leave out the parts of the fragments, which are managed by their own resources
+		var fragments []string
+		resp.Diagnostics.Append(state.Fragments.ElementsAs(ctx, &fragments, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if ownConfig, err = removeAlertmanagerFragments(ownConfig, fragments); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("fragments"),
+				"Invalid Alertmanager fragments",
+				fmt.Sprintf("The fragments can't be removed from the configuration stored in Grafana Mimir: %s", err),
+			)
+			return
+		}
+		state.ConfigYAML = types.StringValue(ownConfig)
+	}
+	// Mimir answers with no templates as an empty map
+	if !reflect.DeepEqual(emptyToNil(fullTemplates), emptyToNil(templates)) {
+		// Leave out the unchanged templates of the fragments
+		ownTemplates := mapStringFromTypesMap(state.TemplatesConfigYAML)
+		for name, content := range templates {
+			if _, ok := ownTemplates[name]; !ok && fullTemplates[name] == content {
+				delete(templates, name)
+			}
+		}
+		state.TemplatesConfigYAML = typeMapFromMapString(emptyToNil(templates))
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -149,10 +670,32 @@ func (r *AlertmanagerResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
-	alertmanagerConfig := plan.ConfigYAML.ValueString()
-	templates := mapStringFromTypesMap(plan.TemplatesConfigYAML)
+	// Write-only secrets are only available in the configuration
+	var secrets types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("secrets"), &secrets)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	fullConfig, templates, err := plan.mergedConfig(ctx)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("fragments"),
+			"Conflicting Alertmanager fragments",
+			fmt.Sprintf("The fragments can't be merged into the configuration: %s", err),
+		)
+		return
+	}
+	alertmanagerConfig, err := substituteAlertmanagerSecrets(fullConfig, mapStringFromTypesMap(secrets))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("secrets"),
+			"Error substituting Alertmanager secrets",
+			err.Error(),
+		)
+		return
+	}
 
-	err := r.client.CreateAlertmanagerConfig(ctx, alertmanagerConfig, templates)
+	err = tenantClient(r.client, plan.TenantID).CreateAlertmanagerConfig(ctx, alertmanagerConfig, templates)
 	if err != nil {
 		tflog.Error(ctx, "Failed to update Alertmanager config via POST", map[string]interface{}{"error": err})
 		resp.Diagnostics.AddError(
@@ -162,12 +705,26 @@ func (r *AlertmanagerResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
-	plan.ID = types.StringValue("alertmanager")
+	plan.ID = alertmanagerID(plan.TenantID)
+
+	// Always fetch the canonical configuration from the backend and store it in state
+	remoteConfig, ok := fetchAlertmanagerConfig(ctx, tenantClient(r.client, plan.TenantID), "UPDATE", &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	plan.RemoteConfigYAML = types.StringValue(normalizeAlertmanagerConfigYAML(remoteConfig, fullConfig))
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func (r *AlertmanagerResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
-	err := r.client.DeleteAlermanagerConfig(ctx)
+func (r *AlertmanagerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AlertmanagerResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := tenantClient(r.client, state.TenantID).DeleteAlermanagerConfig(ctx)
 	if err != nil {
 		tflog.Error(ctx, "Failed to delete Alertmanager config", map[string]interface{}{"error": err})
 		resp.Diagnostics.AddError(
@@ -179,6 +736,23 @@ func (r *AlertmanagerResource) Delete(ctx context.Context, _ resource.DeleteRequ
 	resp.State.RemoveResource(ctx)
 }
 
-func (r *AlertmanagerResource) ImportState(ctx context.Context, _ resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), "alertmanager")...)
+// The import ID is 'alertmanager' to import the configuration of the provider's
+// tenant, or tenant_id=<tenant_id> to import the configuration of another tenant.
+func (r *AlertmanagerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The import ID is alertmanager for the provider tenant, or
+	// tenant_id=<tenant_id> for another tenant
+	tenantID := types.StringNull()
+	if req.ID != "alertmanager" {
+		id, ok := strings.CutPrefix(req.ID, tenantImportIDPrefix)
+		if !ok || id == "" || strings.ContainsAny(id, ":/") {
+			resp.Diagnostics.AddError(
+				"Invalid import ID",
+				fmt.Sprintf("Expected an import ID of the form alertmanager or tenant_id=<tenant_id>, got %q.", req.ID),
+			)
+			return
+		}
+		tenantID = types.StringValue(id)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant_id"), tenantID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), alertmanagerID(tenantID))...)
 }
@@ -1,10 +1,17 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	amconfig "github.com/prometheus/alertmanager/config"
 )
 
 func TestAccResourceAlertmanager(t *testing.T) {
@@ -36,6 +43,297 @@ func TestAccResourceAlertmanagerParseError(t *testing.T) {
 	})
 }
 
+func TestAccResourceAlertmanagerInvalidConfig(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccResourceAlertmanagerInvalidConfig, "receiver: does-not-exist", "severity=\"critical\""),
+				ExpectError: regexp.MustCompile(`undefined receiver "does-not-exist"`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccResourceAlertmanagerInvalidConfig, "receiver: example-email", "severity=~\"(critical\""),
+				ExpectError: regexp.MustCompile("Invalid Alertmanager configuration"),
+			},
+			{
+				Config:      fmt.Sprintf(testAccResourceAlertmanagerInvalidConfig, "receiver: example-email\n  group_wait: 30 seconds", "severity=\"critical\""),
+				ExpectError: regexp.MustCompile("Invalid Alertmanager configuration"),
+			},
+		},
+	})
+}
+
+func TestAccResourceAlertmanagerInvalidTemplates(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccResourceAlertmanagerTemplates, "default_template", `{{ define \"__subject\" }}\n{{ .Status | unknownFunc }}\n{{ end }}`),
+				ExpectError: regexp.MustCompile(`"default_template"\s+is\s+not\s+valid:\s+line\s+2:\s+function\s+"unknownFunc"`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccResourceAlertmanagerTemplates, "default_template", `{{ define \"__subject\" }}{{ .Status }}`),
+				ExpectError: regexp.MustCompile("Invalid Alertmanager template"),
+			},
+			{
+				Config:      fmt.Sprintf(testAccResourceAlertmanagerTemplates, "other_template", `{{ define \"__subject\" }}{{ .Status | toUpper }}{{ end }}`),
+				ExpectError: regexp.MustCompile(`(?s)Missing Alertmanager template.*"default_template"`),
+			},
+		},
+	})
+}
+
+func TestMissingAlertmanagerTemplates(t *testing.T) {
+	templates := map[string]string{"default_template": "", "slack.tmpl": "", "email.tmpl": ""}
+	for _, tc := range []struct {
+		globs   []string
+		missing []string
+	}{
+		{nil, nil},
+		{[]string{"default_template", "*.tmpl"}, nil},
+		{[]string{"slack.tmpl", "pagerduty.tmpl", "*.txt"}, []string{`"pagerduty.tmpl"`, `"*.txt"`}},
+	} {
+		if missing := missingAlertmanagerTemplates(tc.globs, templates); !reflect.DeepEqual(missing, tc.missing) {
+			t.Errorf("globs %v: expected missing templates %v, got %v", tc.globs, tc.missing, missing)
+		}
+	}
+}
+
+func TestAccResourceAlertmanagerEquivalentRemote(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerNoTemplates,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_alertmanager.demo", "remote_config_yaml", testAccResourceAlertmanagerNormalizedYaml),
+					resource.TestCheckNoResourceAttr("mimirtool_alertmanager.demo", "templates_config_yaml.%"),
+				),
+			},
+			{
+				// The same configuration with another layout must not show up as a change
+				PreConfig: func() {
+					if err := testAccMimirClient(t).CreateAlertmanagerConfig(context.Background(), testAccResourceAlertmanagerReorderedYaml, nil); err != nil {
+						t.Fatalf("failed to post Alertmanager config: %s", err)
+					}
+				},
+				Config:   testAccResourceAlertmanagerNoTemplates,
+				PlanOnly: true,
+			},
+			{
+				PreConfig: func() {
+					if err := testAccMimirClient(t).CreateAlertmanagerConfig(context.Background(), strings.Replace(testAccResourceAlertmanagerReorderedYaml, "example-email", "other-email", 2), nil); err != nil {
+						t.Fatalf("failed to post Alertmanager config: %s", err)
+					}
+				},
+				Config:             testAccResourceAlertmanagerNoTemplates,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceAlertmanagerRedactedSecrets(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerSecrets,
+			},
+			{
+				// Secrets redacted by Mimir must not show up as a change
+				PreConfig: func() {
+					if err := testAccMimirClient(t).CreateAlertmanagerConfig(context.Background(), testAccResourceAlertmanagerRedactedYaml, nil); err != nil {
+						t.Fatalf("failed to post Alertmanager config: %s", err)
+					}
+				},
+				Config:   testAccResourceAlertmanagerSecrets,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceAlertmanagerWriteOnlySecrets(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceAlertmanagerWriteOnlySecrets, "password", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("mimirtool_alertmanager.demo", "secrets.%"),
+					resource.TestCheckResourceAttr("mimirtool_alertmanager.demo", "secrets_version", "1"),
+					resource.TestCheckResourceAttr("mimirtool_alertmanager.demo", "config_yaml", testAccResourceAlertmanagerPlaceholdersYaml),
+					testAccCheckAlertmanagerConfig(t, "smtp_auth_password: password"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceAlertmanagerWriteOnlySecrets, "rotated", 2),
+				Check:  testAccCheckAlertmanagerConfig(t, "smtp_auth_password: rotated"),
+			},
+		},
+	})
+}
+
+func TestAccResourceAlertmanagerMissingSecret(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceAlertmanagerMissingSecret,
+				ExpectError: regexp.MustCompile(`(?s)Missing Alertmanager secret.*"smtp_password",\s+"slack_api_url"`),
+			},
+		},
+	})
+}
+
+// testAccCheckAlertmanagerConfig checks the configuration stored in Mimir contains the text.
+func testAccCheckAlertmanagerConfig(t *testing.T, contains string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		alertmanagerConfig, _, err := testAccMimirClient(t).GetAlertmanagerConfig(context.Background())
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(alertmanagerConfig, contains) {
+			return fmt.Errorf("expected the Alertmanager configuration to contain %q, got:\n%s", contains, alertmanagerConfig)
+		}
+		return nil
+	}
+}
+
+func TestSubstituteAlertmanagerSecrets(t *testing.T) {
+	substituted, err := substituteAlertmanagerSecrets(testAccResourceAlertmanagerPlaceholdersYaml, map[string]string{
+		"smtp_password": "pass'word\"",
+		"slack_api_url": "T0000/B0000/XXXX",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := amconfig.Load(substituted)
+	if err != nil {
+		t.Fatalf("invalid substituted configuration: %s\n%s", err, substituted)
+	}
+	if password := string(cfg.Global.SMTPAuthPassword); password != "pass'word\"" {
+		t.Errorf("expected the SMTP password to be substituted, got %q", password)
+	}
+	if url := cfg.Receivers[0].SlackConfigs[0].APIURL.String(); url != "https://hooks.slack.com/services/T0000/B0000/XXXX" {
+		t.Errorf("expected the Slack URL to be substituted, got %q", url)
+	}
+
+	if _, err := substituteAlertmanagerSecrets(testAccResourceAlertmanagerPlaceholdersYaml, map[string]string{"smtp_password": "password"}); err == nil || !strings.Contains(err.Error(), `"slack_api_url"`) {
+		t.Errorf("expected an error about the missing secret, got %v", err)
+	}
+	if substituted, err := substituteAlertmanagerSecrets(testAccResourceAlertmanagerSecretsYaml, nil); err != nil || substituted != testAccResourceAlertmanagerSecretsYaml {
+		t.Errorf("expected a configuration without placeholders to be left as is, got %q (%v)", substituted, err)
+	}
+}
+
+func TestNormalizeAlertmanagerConfigYAMLPlaceholders(t *testing.T) {
+	const desired = `
+route:
+  receiver: webhook
+receivers:
+  - name: webhook
+    webhook_configs:
+      - url_file: /etc/alertmanager/url
+        http_config:
+          http_headers:
+            X-Api-Token:
+              values: ['<secret:api_token>']
+  - name: email
+    email_configs:
+      - to: 'oncall+<secret:mailbox>@example.org'
+`
+	substituted, err := substituteAlertmanagerSecrets(desired, map[string]string{"api_token": "s3cr3t-token", "mailbox": "s3cr3t-mailbox"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	normalized := normalizeAlertmanagerConfigYAML(substituted, desired)
+	for _, secret := range []string{"s3cr3t-token", "s3cr3t-mailbox"} {
+		if strings.Contains(normalized, secret) {
+			t.Errorf("expected the secret %q to be redacted, got:\n%s", secret, normalized)
+		}
+	}
+	if !strings.Contains(normalized, "/etc/alertmanager/url") {
+		t.Errorf("expected the values without placeholders to be kept, got:\n%s", normalized)
+	}
+	if !alertmanagerConfigsEqual(desired, normalized) {
+		t.Errorf("expected the configuration to match its redacted version")
+	}
+}
+
+func TestAlertmanagerConfigsEqual(t *testing.T) {
+	for _, tc := range []struct {
+		a, b  string
+		equal bool
+	}{
+		{testAccResourceAlertmanagerNoTemplatesYaml, testAccResourceAlertmanagerNoTemplatesYaml, true},
+		{testAccResourceAlertmanagerNoTemplatesYaml, testAccResourceAlertmanagerReorderedYaml, true},
+		{testAccResourceAlertmanagerNoTemplatesYaml, testAccResourceAlertmanagerNormalizedYaml, true},
+		{testAccResourceAlertmanagerNoTemplatesYaml, strings.Replace(testAccResourceAlertmanagerReorderedYaml, "example-email", "other-email", 2), false},
+		{testAccResourceAlertmanagerNoTemplatesYaml, "foo: bar: baz", false},
+		{testAccResourceAlertmanagerSecretsYaml, testAccResourceAlertmanagerRedactedYaml, true},
+		{testAccResourceAlertmanagerRedactedYaml, testAccResourceAlertmanagerSecretsYaml, false},
+		{testAccResourceAlertmanagerSecretsYaml, strings.Replace(testAccResourceAlertmanagerSecretsYaml, "slack", "example", 1), false},
+		{testAccResourceAlertmanagerSecretsYaml, strings.Replace(testAccResourceAlertmanagerRedactedYaml, "#alerts", "<secret>", 1), false},
+		{testAccResourceAlertmanagerPlaceholdersYaml, testAccResourceAlertmanagerSecretsYaml, true},
+		{testAccResourceAlertmanagerPlaceholdersYaml, testAccResourceAlertmanagerRedactedYaml, true},
+		{testAccResourceAlertmanagerPlaceholdersYaml, strings.Replace(testAccResourceAlertmanagerSecretsYaml, "https://hooks.slack.com/services/", "https://example.org/", 1), false},
+	} {
+		if equal := alertmanagerConfigsEqual(tc.a, tc.b); equal != tc.equal {
+			t.Errorf("expected alertmanagerConfigsEqual(%q, %q) to be %t", tc.a, tc.b, tc.equal)
+		}
+	}
+}
+
+func TestAlertmanagerSecretsByPath(t *testing.T) {
+	const configYAML = `
+route:
+  receiver: default
+receivers:
+  - name: default
+    webhook_configs:
+      - url: https://example.org/hook
+        http_config:
+          authorization:
+            credentials: token
+    pagerduty_configs:
+      - routing_key: routing-secret
+        url: https://events.pagerduty.com/v2/enqueue
+    slack_configs:
+      - api_url: https://hooks.slack.com/services/T0000/B0000/XXXX
+        actions:
+          - type: button
+            text: Runbook
+            url: https://runbooks.example.org
+`
+	normalized := normalizeAlertmanagerConfigYAML(configYAML, configYAML)
+	for _, secret := range []string{"https://example.org/hook", "token", "routing-secret", "hooks.slack.com"} {
+		if strings.Contains(normalized, secret) {
+			t.Errorf("expected %q to be redacted, got:\n%s", secret, normalized)
+		}
+	}
+	for _, value := range []string{"https://events.pagerduty.com/v2/enqueue", "https://runbooks.example.org"} {
+		if !strings.Contains(normalized, value) {
+			t.Errorf("expected %q not to be redacted, got:\n%s", value, normalized)
+		}
+	}
+
+	// Changes to the URLs which are not secrets are detected
+	if !alertmanagerConfigsEqual(configYAML, normalized) {
+		t.Errorf("expected the configuration to match its redacted version")
+	}
+	for _, url := range []string{"https://events.pagerduty.com/v2/enqueue", "https://runbooks.example.org"} {
+		if alertmanagerConfigsEqual(configYAML, strings.Replace(normalized, url, alertmanagerSecretMask, 1)) {
+			t.Errorf("expected a redacted %q not to match the configured one", url)
+		}
+	}
+}
+
 func TestAccResourceAlertmanagerImport(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -52,6 +350,56 @@ func TestAccResourceAlertmanagerImport(t *testing.T) {
 	})
 }
 
+func TestAccResourceAlertmanagerTenant(t *testing.T) {
+	// The Mimir instance used for acceptance tests has multitenancy disabled
+	server := newTestMimirServer(t, "/prometheus", "/alertmanager")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceAlertmanagerTenant, server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_alertmanager.demo", "id", "tenant-a"),
+					resource.TestCheckResourceAttr("mimirtool_alertmanager.demo", "tenant_id", "tenant-a"),
+				),
+			},
+			{
+				ResourceName:      "mimirtool_alertmanager.demo",
+				ImportStateId:     "tenant_id=tenant-a",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  "mimirtool_alertmanager.demo",
+				ImportStateId: "tenant-a",
+				ImportState:   true,
+				ExpectError:   regexp.MustCompile("Invalid import ID"),
+			},
+			{
+				ResourceName:  "mimirtool_alertmanager.demo",
+				ImportStateId: "tenant_id=tenant-a:garbage",
+				ImportState:   true,
+				ExpectError:   regexp.MustCompile("Invalid import ID"),
+			},
+		},
+	})
+}
+
+const testAccResourceAlertmanagerTenant = `
+provider "mimirtool" {
+  address = %q
+}
+
+resource "mimirtool_alertmanager" "demo" {
+	tenant_id = "tenant-a"
+	config_yaml = file("testdata/example_alertmanager_config.yaml")
+	templates_config_yaml = {
+	  default_template = file("testdata/example_alertmanager_template.tmpl")
+	}
+}
+`
+
 const testAccResourceAlertmanager = `
 provider "mimirtool" {
   address = "http://localhost:8080"
@@ -83,6 +431,133 @@ const testAccResourceAlertmanagerTemplate = `{{ define "__alertmanager" }}AlertM
 {{ define "__alertmanagerURL" }}{{ .ExternalURL }}/#/alerts?receiver={{ .Receiver | urlquery }}{{ end }}
 `
 
+const testAccResourceAlertmanagerNoTemplates = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_alertmanager" "demo" {
+  config_yaml = <<YAML
+` + testAccResourceAlertmanagerNoTemplatesYaml + `YAML
+}
+`
+
+const testAccResourceAlertmanagerNoTemplatesYaml = `# Comments are not stored
+route:
+  receiver: example-email
+  group_by: ['alertname']
+receivers:
+  - name: example-email
+    email_configs:
+      - to: 'youraddress@example.org'
+        from: 'alertmanager@example.org'
+        smarthost: 'localhost:25'
+`
+
+const testAccResourceAlertmanagerReorderedYaml = `receivers:
+- email_configs:
+  - from: alertmanager@example.org
+    smarthost: localhost:25
+    to: youraddress@example.org
+  name: example-email
+route:
+  group_by:
+  - alertname
+  receiver: example-email
+`
+
+const testAccResourceAlertmanagerNormalizedYaml = `receivers:
+    - email_configs:
+        - from: alertmanager@example.org
+          smarthost: localhost:25
+          to: youraddress@example.org
+      name: example-email
+route:
+    group_by:
+        - alertname
+    receiver: example-email
+`
+
+const testAccResourceAlertmanagerSecrets = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_alertmanager" "demo" {
+  config_yaml = <<YAML
+` + testAccResourceAlertmanagerSecretsYaml + `YAML
+}
+`
+
+const testAccResourceAlertmanagerSecretsYaml = `global:
+  smtp_smarthost: 'localhost:25'
+  smtp_from: 'alertmanager@example.org'
+  smtp_auth_username: 'alertmanager'
+  smtp_auth_password: 'password'
+route:
+  receiver: slack
+receivers:
+  - name: slack
+    slack_configs:
+      - api_url: 'https://hooks.slack.com/services/T0000/B0000/XXXX'
+        channel: '#alerts'
+`
+
+const testAccResourceAlertmanagerRedactedYaml = `global:
+  smtp_smarthost: 'localhost:25'
+  smtp_from: 'alertmanager@example.org'
+  smtp_auth_username: 'alertmanager'
+  smtp_auth_password: '<secret>'
+route:
+  receiver: slack
+receivers:
+  - name: slack
+    slack_configs:
+      - api_url: '<secret>'
+        channel: '#alerts'
+`
+
+const testAccResourceAlertmanagerWriteOnlySecrets = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_alertmanager" "demo" {
+  config_yaml = <<YAML
+` + testAccResourceAlertmanagerPlaceholdersYaml + `YAML
+  secrets = {
+    smtp_password = %q
+    slack_api_url = "T0000/B0000/XXXX"
+  }
+  secrets_version = %d
+}
+`
+
+const testAccResourceAlertmanagerMissingSecret = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_alertmanager" "demo" {
+  config_yaml = <<YAML
+` + testAccResourceAlertmanagerPlaceholdersYaml + `YAML
+}
+`
+
+const testAccResourceAlertmanagerPlaceholdersYaml = `global:
+  smtp_smarthost: 'localhost:25'
+  smtp_from: 'alertmanager@example.org'
+  smtp_auth_username: 'alertmanager'
+  smtp_auth_password: '<secret:smtp_password>'
+route:
+  receiver: slack
+receivers:
+  - name: slack
+    slack_configs:
+      - api_url: 'https://hooks.slack.com/services/<secret:slack_api_url>'
+        channel: '#alerts'
+`
+
 const testAccResourceAlertmanagerParseError = `
 provider "mimirtool" {
   address = "http://localhost:8080"
@@ -94,3 +569,35 @@ foo: bar: baz
 YAML
 }
 `
+
+const testAccResourceAlertmanagerInvalidConfig = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_alertmanager" "demo" {
+  config_yaml = <<YAML
+route:
+  %s
+  routes:
+    - receiver: example-email
+      matchers:
+        - '%s'
+receivers:
+  - name: example-email
+YAML
+}
+`
+
+const testAccResourceAlertmanagerTemplates = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_alertmanager" "demo" {
+	config_yaml = file("testdata/example_alertmanager_config.yaml")
+	templates_config_yaml = {
+	  %s = "%s"
+	}
+}
+`
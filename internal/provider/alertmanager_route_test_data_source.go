@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AlertmanagerRouteTestDataSource{}
+
+func NewAlertmanagerRouteTestDataSource() datasource.DataSource {
+	return &AlertmanagerRouteTestDataSource{}
+}
+
+// AlertmanagerRouteTestDataSource finds the routes an Alertmanager configuration
+// sends alerts to, like `amtool config routes test`. It does not call Mimir.
+type AlertmanagerRouteTestDataSource struct{}
+
+// AlertmanagerRouteTestDataSourceModel describes the data source data model.
+type AlertmanagerRouteTestDataSourceModel struct {
+	ID         types.String           `tfsdk:"id"`
+	ConfigYAML types.String           `tfsdk:"config_yaml"`
+	LabelSets  []types.Map            `tfsdk:"label_sets"`
+	Results    []routeTestResultModel `tfsdk:"results"`
+}
+
+type routeTestResultModel struct {
+	Labels    types.Map  `tfsdk:"labels"`
+	Receivers types.List `tfsdk:"receivers"`
+	Routes    types.List `tfsdk:"routes"`
+}
+
+func (d *AlertmanagerRouteTestDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alertmanager_route_test"
+}
+
+func (d *AlertmanagerRouteTestDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Tests the routing tree of an Alertmanager configuration against label sets, like `amtool config routes test`. The routes are computed locally, without calling Grafana Mimir.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "hash",
+			},
+			"config_yaml": schema.StringAttribute{
+				MarkdownDescription: "The Alertmanager configuration to test as YAML.",
+				Required:            true,
+				Validators: []validator.String{
+					yamlSyntaxValidator{},
+					alertmanagerConfigValidator{},
+				},
+			},
+			"label_sets": schema.ListAttribute{
+				MarkdownDescription: "The label sets of the alerts to route.",
+				ElementType:         types.MapType{ElemType: types.StringType},
+				Required:            true,
+			},
+			"results": schema.ListNestedAttribute{
+				MarkdownDescription: "The routing of each label set, in the order of `label_sets`.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"labels": schema.MapAttribute{
+							MarkdownDescription: "The label set.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"receivers": schema.ListAttribute{
+							MarkdownDescription: "The receivers the alert is sent to, in the order of the matching routes.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"routes": schema.ListAttribute{
+							MarkdownDescription: "The paths of the matching routes, made of the matchers of each route from the root route and of the position of the route among its siblings, such as `{}/{team=\"a\"}/0`.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AlertmanagerRouteTestDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AlertmanagerRouteTestDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cfg, err := loadAlertmanagerConfig(data.ConfigYAML.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("config_yaml"),
+			"Invalid Alertmanager configuration",
+			fmt.Sprintf("Alertmanager configuration is not valid: %s", err.Error()),
+		)
+		return
+	}
+	root := dispatch.NewRoute(cfg.Route, nil)
+
+	data.Results = make([]routeTestResultModel, 0, len(data.LabelSets))
+	for i, labelSet := range data.LabelSets {
+		labels := mapStringFromTypesMap(labelSet)
+		lset := make(model.LabelSet, len(labels))
+		for name, value := range labels {
+			if !model.LabelName(name).IsValid() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("label_sets").AtListIndex(i),
+					"Invalid label name",
+					fmt.Sprintf("%q is not a valid label name", name),
+				)
+				return
+			}
+			lset[model.LabelName(name)] = model.LabelValue(value)
+		}
+
+		var receivers, routes []string
+		for _, route := range root.Match(lset) {
+			receivers = append(receivers, route.RouteOpts.Receiver)
+			routes = append(routes, route.ID())
+		}
+		receiversValue, diags := types.ListValueFrom(ctx, types.StringType, receivers)
+		resp.Diagnostics.Append(diags...)
+		routesValue, diags := types.ListValueFrom(ctx, types.StringType, routes)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.Results = append(data.Results, routeTestResultModel{
+			Labels:    labelSet,
+			Receivers: receiversValue,
+			Routes:    routesValue,
+		})
+	}
+
+	data.ID = types.StringValue(hash(data.ConfigYAML.ValueString() + labelSetsKey(data.LabelSets)))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// labelSetsKey returns a stable representation of the label sets.
+func labelSetsKey(labelSets []types.Map) string {
+	var b strings.Builder
+	for _, labelSet := range labelSets {
+		labels := mapStringFromTypesMap(labelSet)
+		names := make([]string, 0, len(labels))
+		for name := range labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		b.WriteString("\n")
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s=%q,", name, labels[name])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceAlertmanagerRouteTest(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAlertmanagerRouteTest,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.#", "3"),
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.0.labels.team", "a"),
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.0.receivers.#", "2"),
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.0.receivers.0", "team-a"),
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.0.receivers.1", "pager"),
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.0.routes.0", `{}/{team="a"}/0`),
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.0.routes.1", `{}/{severity="critical"}/1`),
+
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.1.receivers.#", "1"),
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.1.receivers.0", "team-a"),
+
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.2.receivers.#", "1"),
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.2.receivers.0", "default"),
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_route_test.demo", "results.2.routes.0", "{}"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceAlertmanagerRouteTestInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceAlertmanagerRouteTestInvalidLabel,
+				ExpectError: regexp.MustCompile(`"team-name" is not a valid label name`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceAlertmanagerRouteTest = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+data "mimirtool_alertmanager_route_test" "demo" {
+  config_yaml = <<YAML
+route:
+  receiver: default
+  routes:
+    - receiver: team-a
+      matchers: ['team="a"']
+      continue: true
+    - receiver: pager
+      matchers: ['severity="critical"']
+receivers:
+  - name: default
+  - name: team-a
+  - name: pager
+YAML
+  label_sets = [
+    { team = "a", severity = "critical" },
+    { team = "a", severity = "warning" },
+    { team = "b" },
+  ]
+}
+`
+
+const testAccDataSourceAlertmanagerRouteTestInvalidLabel = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+data "mimirtool_alertmanager_route_test" "demo" {
+  config_yaml = <<YAML
+route:
+  receiver: default
+receivers:
+  - name: default
+YAML
+  label_sets = [
+    { "team-name" = "a" },
+  ]
+}
+`
@@ -0,0 +1,560 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/prometheus/common/model"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                   = &AlertmanagerSilenceResource{}
+	_ resource.ResourceWithImportState    = &AlertmanagerSilenceResource{}
+	_ resource.ResourceWithValidateConfig = &AlertmanagerSilenceResource{}
+	_ resource.ResourceWithModifyPlan     = &AlertmanagerSilenceResource{}
+)
+
+func NewAlertmanagerSilenceResource() resource.Resource {
+	return &AlertmanagerSilenceResource{}
+}
+
+// AlertmanagerSilenceResource defines the resource implementation. Alertmanager
+// never deletes silences, destroying the resource expires the silence.
+type AlertmanagerSilenceResource struct {
+	client mimirClientInterface
+}
+
+// AlertmanagerSilenceResourceModel describes the resource data model.
+type AlertmanagerSilenceResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Matchers  types.List   `tfsdk:"matchers"`
+	StartsAt  types.String `tfsdk:"starts_at"`
+	EndsAt    types.String `tfsdk:"ends_at"`
+	Duration  types.String `tfsdk:"duration"`
+	Comment   types.String `tfsdk:"comment"`
+	CreatedBy types.String `tfsdk:"created_by"`
+	Status    types.String `tfsdk:"status"`
+	TenantID  types.String `tfsdk:"tenant_id"`
+}
+
+type silenceMatcherModel struct {
+	Name    types.String `tfsdk:"name"`
+	Value   types.String `tfsdk:"value"`
+	IsRegex types.Bool   `tfsdk:"is_regex"`
+	IsEqual types.Bool   `tfsdk:"is_equal"`
+}
+
+var silenceMatcherAttrTypes = map[string]attr.Type{
+	"name":     types.StringType,
+	"value":    types.StringType,
+	"is_regex": types.BoolType,
+	"is_equal": types.BoolType,
+}
+
+func (r *AlertmanagerSilenceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alertmanager_silence"
+}
+
+func (r *AlertmanagerSilenceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a silence of the Grafana Mimir Alertmanager. Destroying the resource expires the silence. " +
+			"A silence which expired before its configured `ends_at`, or which lasts for a `duration`, is created again; " +
+			"a silence whose `ends_at` has passed is kept with the `expired` status. " +
+			"[Official documentation](https://grafana.com/docs/mimir/latest/references/http-api/#alertmanager)",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The ID of the silence. Alertmanager may give a new ID to the silence when it is updated.",
+			},
+			"matchers": schema.ListNestedAttribute{
+				MarkdownDescription: "The matchers selecting the alerts to silence. Changing them creates a new silence.",
+				Required:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the label to match.",
+							Required:            true,
+						},
+						"value": schema.StringAttribute{
+							MarkdownDescription: "The value, or the regular expression when `is_regex` is set, to match.",
+							Required:            true,
+						},
+						"is_regex": schema.BoolAttribute{
+							MarkdownDescription: "Whether `value` is a regular expression. Defaults to `false`.",
+							Optional:            true,
+							Computed:            true,
+							Default:             booldefault.StaticBool(false),
+						},
+						"is_equal": schema.BoolAttribute{
+							MarkdownDescription: "Whether the label must match `value`, or must not match it. Defaults to `true`.",
+							Optional:            true,
+							Computed:            true,
+							Default:             booldefault.StaticBool(true),
+						},
+					},
+				},
+			},
+			"starts_at": schema.StringAttribute{
+				MarkdownDescription: "When the silence starts, in RFC 3339 format. Defaults to the creation time of the silence.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ends_at": schema.StringAttribute{
+				MarkdownDescription: "When the silence ends, in RFC 3339 format. Computed from `duration` when not set. Exactly one of `ends_at` or `duration` must be set.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"duration": schema.StringAttribute{
+				MarkdownDescription: "How long the silence lasts from `starts_at`, as a Prometheus duration such as `2h` or `1d`. Exactly one of `ends_at` or `duration` must be set.",
+				Optional:            true,
+			},
+			"comment": schema.StringAttribute{
+				MarkdownDescription: "Why the alerts are silenced.",
+				Required:            true,
+			},
+			"created_by": schema.StringAttribute{
+				MarkdownDescription: "Who created the silence.",
+				Required:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The state of the silence: `pending`, `active` or `expired`.",
+				Computed:            true,
+			},
+			"tenant_id": schema.StringAttribute{
+				MarkdownDescription: "Tenant ID to manage the silence in. Overrides the provider `tenant_id`.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AlertmanagerSilenceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(mimirClientInterface)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected mimirClientInterface, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// ValidateConfig checks the matchers and the times of the silence.
+func (r *AlertmanagerSilenceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config AlertmanagerSilenceResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Matchers.IsNull() || (!config.Matchers.IsUnknown() && len(config.Matchers.Elements()) == 0) {
+		resp.Diagnostics.AddAttributeError(path.Root("matchers"), "Invalid silence", "A silence needs at least one matcher.")
+	}
+	if isFullyKnown(ctx, config.Matchers) {
+		var matchers []silenceMatcherModel
+		resp.Diagnostics.Append(config.Matchers.ElementsAs(ctx, &matchers, false)...)
+		for i, m := range matchers {
+			matcherPath := path.Root("matchers").AtListIndex(i)
+			if !model.LabelName(m.Name.ValueString()).IsValid() {
+				resp.Diagnostics.AddAttributeError(matcherPath.AtName("name"), "Invalid label name", fmt.Sprintf("%q is not a valid label name.", m.Name.ValueString()))
+			}
+			if m.IsRegex.ValueBool() {
+				if _, err := regexp.Compile("^(?:" + m.Value.ValueString() + ")$"); err != nil {
+					resp.Diagnostics.AddAttributeError(matcherPath.AtName("value"), "Invalid regular expression", err.Error())
+				}
+			}
+		}
+	}
+
+	if config.EndsAt.IsUnknown() || config.Duration.IsUnknown() {
+		return
+	}
+	if config.EndsAt.IsNull() == config.Duration.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("duration"), "Invalid silence", "Exactly one of ends_at or duration must be set.")
+		return
+	}
+	startsAt, ok := parseSilenceTime(config.StartsAt, path.Root("starts_at"), &resp.Diagnostics)
+	endsAt, ok2 := parseSilenceTime(config.EndsAt, path.Root("ends_at"), &resp.Diagnostics)
+	if !config.Duration.IsNull() {
+		if d, err := model.ParseDuration(config.Duration.ValueString()); err != nil || d <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("duration"), "Invalid duration", fmt.Sprintf("%q is not a positive Prometheus duration.", config.Duration.ValueString()))
+		}
+	}
+	if ok && ok2 && !startsAt.IsZero() && !endsAt.IsZero() && !endsAt.After(startsAt) {
+		resp.Diagnostics.AddAttributeError(path.Root("ends_at"), "Invalid silence", "ends_at must be after starts_at.")
+	}
+}
+
+// ModifyPlan computes ends_at from the duration, when starts_at is known.
+func (r *AlertmanagerSilenceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan AlertmanagerSilenceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Duration.IsNull() {
+		return
+	}
+
+	endsAt := types.StringUnknown()
+	if !plan.Duration.IsUnknown() && !plan.StartsAt.IsUnknown() {
+		startsAt, err := time.Parse(time.RFC3339, plan.StartsAt.ValueString())
+		d, err2 := model.ParseDuration(plan.Duration.ValueString())
+		if err == nil && err2 == nil {
+			endsAt = types.StringValue(formatSilenceTime(startsAt.Add(time.Duration(d))))
+		}
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ends_at"), endsAt)...)
+}
+
+func (r *AlertmanagerSilenceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AlertmanagerSilenceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if plan.StartsAt.IsUnknown() {
+		plan.StartsAt = types.StringValue(formatSilenceTime(now))
+	}
+	silence, ok := plan.silence(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	if plan.EndsAt.IsUnknown() {
+		plan.EndsAt = types.StringValue(formatSilenceTime(silence.EndsAt))
+	}
+	if !silence.EndsAt.After(now) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ends_at"),
+			"Silence already ended",
+			fmt.Sprintf("The silence would end at %s, which is in the past. Update starts_at, ends_at or duration to create it.", plan.EndsAt.ValueString()),
+		)
+		return
+	}
+
+	tflog.Info(ctx, "Creating Alertmanager silence", map[string]any{"tenant_id": plan.TenantID.ValueString()})
+	id, err := tenantClient(r.client, plan.TenantID).CreateSilence(ctx, silence)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to create silence",
+			fmt.Sprintf("Could not create the Alertmanager silence: %s", err.Error()),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(id)
+	plan.Status = types.StringValue(silenceState(silence, now))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *AlertmanagerSilenceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state AlertmanagerSilenceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote, err := tenantClient(r.client, state.TenantID).GetSilence(ctx, state.ID.ValueString())
+	if err != nil {
+		if errors.Is(err, client.ErrResourceNotFound) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading Alertmanager silence",
+			fmt.Sprintf("Could not read Alertmanager silence %q: %s", state.ID.ValueString(), err.Error()),
+		)
+		return
+	}
+	if remote.Status != nil && remote.Status.State == "expired" {
+		// A silence whose configured ends_at has passed is kept: its window is
+		// over and creating it again would fail. Only silences which expired
+		// early, or which last for a duration, are created again.
+		if endsAt, err := time.Parse(time.RFC3339, state.EndsAt.ValueString()); err == nil && state.Duration.IsNull() && !endsAt.After(time.Now()) {
+			state.Status = types.StringValue("expired")
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			return
+		}
+		resp.Diagnostics.AddWarning(
+			"Silence expired",
+			fmt.Sprintf("Alertmanager silence %q has expired and will be re-created.", state.ID.ValueString()),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(state.setSilence(ctx, *remote)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *AlertmanagerSilenceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state AlertmanagerSilenceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	silence, ok := plan.silence(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	if plan.EndsAt.IsUnknown() {
+		plan.EndsAt = types.StringValue(formatSilenceTime(silence.EndsAt))
+	}
+	if !silence.EndsAt.After(now) {
+		// The window of an expired silence is over, there is nothing to update
+		if state.Status.ValueString() == "expired" {
+			plan.ID = state.ID
+			plan.Status = state.Status
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+			return
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ends_at"),
+			"Silence already ended",
+			fmt.Sprintf("The silence would end at %s, which is in the past. Destroy the resource to expire the silence.", plan.EndsAt.ValueString()),
+		)
+		return
+	}
+
+	// Posting a silence with its ID updates it, Alertmanager replaces it by a
+	// new silence when it can't be updated in place
+	silence.ID = state.ID.ValueString()
+	id, err := tenantClient(r.client, plan.TenantID).CreateSilence(ctx, silence)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to update silence",
+			fmt.Sprintf("Could not update Alertmanager silence %q: %s", silence.ID, err.Error()),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(id)
+	plan.Status = types.StringValue(silenceState(silence, now))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *AlertmanagerSilenceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AlertmanagerSilenceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Expiring a silence which already expired fails
+	cli := tenantClient(r.client, state.TenantID)
+	remote, err := cli.GetSilence(ctx, state.ID.ValueString())
+	if err == nil && remote.Status != nil && remote.Status.State == "expired" {
+		return
+	}
+	if err == nil {
+		err = cli.ExpireSilence(ctx, state.ID.ValueString())
+	}
+	if err != nil && !errors.Is(err, client.ErrResourceNotFound) {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Resource",
+			"An unexpected error occurred while attempting to delete the resource. "+
+				"Please retry the operation or report this issue to the provider developers.\n\n"+
+				"HTTP Error: "+err.Error(),
+		)
+	}
+}
+
+func (r *AlertmanagerSilenceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The import ID is the silence ID, optionally prefixed by the tenant ID
+	tenantID, id := splitTenantImportID(req.ID)
+	if id == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form [tenant_id=<tenant_id>:]<silence_id>, got %q.", req.ID),
+		)
+		return
+	}
+
+	remote, err := tenantClient(r.client, tenantID).GetSilence(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Alertmanager silence after IMPORT",
+			fmt.Sprintf("Could not read Alertmanager silence %q: %s", id, err.Error()),
+		)
+		return
+	}
+
+	state := AlertmanagerSilenceResourceModel{
+		ID:       types.StringValue(id),
+		Matchers: types.ListNull(types.ObjectType{AttrTypes: silenceMatcherAttrTypes}),
+		StartsAt: types.StringNull(),
+		EndsAt:   types.StringNull(),
+		Duration: types.StringNull(),
+		TenantID: tenantID,
+	}
+	resp.Diagnostics.Append(state.setSilence(ctx, *remote)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// silence converts the planned silence, computing its end from the duration
+// when ends_at is not known.
+func (m AlertmanagerSilenceResourceModel) silence(ctx context.Context, diagnostics *diag.Diagnostics) (alertmanagerSilence, bool) {
+	silence := alertmanagerSilence{
+		Comment:   m.Comment.ValueString(),
+		CreatedBy: m.CreatedBy.ValueString(),
+	}
+	var matchers []silenceMatcherModel
+	diagnostics.Append(m.Matchers.ElementsAs(ctx, &matchers, false)...)
+	for _, matcher := range matchers {
+		silence.Matchers = append(silence.Matchers, alertmanagerSilenceMatcher{
+			Name:    matcher.Name.ValueString(),
+			Value:   matcher.Value.ValueString(),
+			IsRegex: matcher.IsRegex.ValueBool(),
+			IsEqual: matcher.IsEqual.ValueBool(),
+		})
+	}
+
+	var ok bool
+	if silence.StartsAt, ok = parseSilenceTime(m.StartsAt, path.Root("starts_at"), diagnostics); !ok {
+		return silence, false
+	}
+	if !m.EndsAt.IsUnknown() && !m.EndsAt.IsNull() {
+		silence.EndsAt, ok = parseSilenceTime(m.EndsAt, path.Root("ends_at"), diagnostics)
+		return silence, ok && !diagnostics.HasError()
+	}
+	d, err := model.ParseDuration(m.Duration.ValueString())
+	if err != nil {
+		diagnostics.AddAttributeError(path.Root("duration"), "Invalid duration", err.Error())
+		return silence, false
+	}
+	silence.EndsAt = silence.StartsAt.Add(time.Duration(d))
+	return silence, !diagnostics.HasError()
+}
+
+// setSilence updates the model from the silence read from Alertmanager. The
+// times and matchers of the model are kept when they are equivalent, and
+// starts_at is only set on import as Alertmanager moves it to the creation time
+// of silences starting in the past.
+func (m *AlertmanagerSilenceResourceModel) setSilence(ctx context.Context, silence alertmanagerSilence) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var matchers []silenceMatcherModel
+	if !m.Matchers.IsNull() {
+		diags.Append(m.Matchers.ElementsAs(ctx, &matchers, false)...)
+	}
+	if !silenceMatchersEqual(matchers, silence.Matchers) {
+		matchers = make([]silenceMatcherModel, 0, len(silence.Matchers))
+		for _, matcher := range silence.Matchers {
+			matchers = append(matchers, silenceMatcherModel{
+				Name:    types.StringValue(matcher.Name),
+				Value:   types.StringValue(matcher.Value),
+				IsRegex: types.BoolValue(matcher.IsRegex),
+				IsEqual: types.BoolValue(matcher.IsEqual),
+			})
+		}
+		var listDiags diag.Diagnostics
+		m.Matchers, listDiags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: silenceMatcherAttrTypes}, matchers)
+		diags.Append(listDiags...)
+	}
+
+	if m.StartsAt.IsNull() {
+		m.StartsAt = types.StringValue(formatSilenceTime(silence.StartsAt))
+	}
+	if endsAt, err := time.Parse(time.RFC3339, m.EndsAt.ValueString()); err != nil || !endsAt.Equal(silence.EndsAt) {
+		m.EndsAt = types.StringValue(formatSilenceTime(silence.EndsAt))
+	}
+	m.Comment = types.StringValue(silence.Comment)
+	m.CreatedBy = types.StringValue(silence.CreatedBy)
+	m.Status = types.StringNull()
+	if silence.Status != nil {
+		m.Status = types.StringValue(silence.Status.State)
+	}
+	return diags
+}
+
+// silenceMatchersEqual reports whether the matchers are the same, regardless
+// of their order.
+func silenceMatchersEqual(models []silenceMatcherModel, matchers []alertmanagerSilenceMatcher) bool {
+	if len(models) != len(matchers) {
+		return false
+	}
+	remaining := append([]alertmanagerSilenceMatcher(nil), matchers...)
+	for _, m := range models {
+		found := false
+		for i, matcher := range remaining {
+			if matcher.Name == m.Name.ValueString() && matcher.Value == m.Value.ValueString() &&
+				matcher.IsRegex == m.IsRegex.ValueBool() && matcher.IsEqual == m.IsEqual.ValueBool() {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// silenceState returns the state Alertmanager gives to the silence at now.
+func silenceState(silence alertmanagerSilence, now time.Time) string {
+	switch {
+	case !silence.EndsAt.After(now):
+		return "expired"
+	case silence.StartsAt.After(now):
+		return "pending"
+	}
+	return "active"
+}
+
+// parseSilenceTime parses an RFC 3339 time, a null or unknown value is
+// returned as the zero time.
+func parseSilenceTime(value types.String, p path.Path, diagnostics *diag.Diagnostics) (time.Time, bool) {
+	if value.IsNull() || value.IsUnknown() {
+		return time.Time{}, true
+	}
+	t, err := time.Parse(time.RFC3339, value.ValueString())
+	if err != nil {
+		diagnostics.AddAttributeError(p, "Invalid time", fmt.Sprintf("%q is not an RFC 3339 time: %s", value.ValueString(), err.Error()))
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func formatSilenceTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
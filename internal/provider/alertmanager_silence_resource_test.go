@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourceAlertmanagerSilence(t *testing.T) {
+	var silenceID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerSilence("Planned maintenance"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_silence.demo", "status", "active"),
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_silence.demo", "matchers.0.is_equal", "true"),
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_silence.demo", "matchers.1.is_regex", "true"),
+					resource.TestCheckResourceAttrSet("mimirtool_alertmanager_silence.demo", "ends_at"),
+					resource.TestCheckResourceAttrWith("mimirtool_alertmanager_silence.demo", "id", func(id string) error {
+						silenceID = id
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccResourceAlertmanagerSilence("Extended maintenance"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_silence.demo", "comment", "Extended maintenance"),
+					resource.TestCheckResourceAttrPtr("mimirtool_alertmanager_silence.demo", "id", &silenceID),
+				),
+			},
+			{
+				ResourceName:            "mimirtool_alertmanager_silence.demo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"duration", "starts_at"},
+			},
+			{
+				// A silence which expired outside of Terraform is created again
+				PreConfig: func() {
+					if err := testAccMimirClient(t).ExpireSilence(context.Background(), silenceID); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccResourceAlertmanagerSilence("Extended maintenance"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_silence.demo", "status", "active"),
+					resource.TestCheckResourceAttrWith("mimirtool_alertmanager_silence.demo", "id", func(id string) error {
+						if id == silenceID {
+							t.Errorf("expected a new silence, got %s", id)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceAlertmanagerSilencePending(t *testing.T) {
+	startsAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerSilencePending(startsAt.Format(time.RFC3339), startsAt.Add(time.Hour).Format(time.RFC3339)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_silence.pending", "status", "pending"),
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_silence.pending", "starts_at", startsAt.Format(time.RFC3339)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceAlertmanagerSilenceWindowEnded(t *testing.T) {
+	startsAt := time.Now().UTC().Truncate(time.Second)
+	endsAt := startsAt.Add(10 * time.Second)
+	config := testAccResourceAlertmanagerSilencePending(startsAt.Format(time.RFC3339), endsAt.Format(time.RFC3339))
+	var silenceID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_silence.pending", "status", "active"),
+					resource.TestCheckResourceAttrWith("mimirtool_alertmanager_silence.pending", "id", func(id string) error {
+						silenceID = id
+						return nil
+					}),
+				),
+			},
+			{
+				// Once its window is over, the silence is kept in state
+				PreConfig: func() {
+					time.Sleep(time.Until(endsAt.Add(time.Second)))
+				},
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_silence.pending", "status", "expired"),
+					resource.TestCheckResourceAttrPtr("mimirtool_alertmanager_silence.pending", "id", &silenceID),
+				),
+			},
+			{
+				// Updating a silence whose window is over does not create it again
+				Config: strings.Replace(config, `"Upgrade"`, `"Upgrade done"`, 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_silence.pending", "status", "expired"),
+					resource.TestCheckResourceAttr("mimirtool_alertmanager_silence.pending", "comment", "Upgrade done"),
+					resource.TestCheckResourceAttrPtr("mimirtool_alertmanager_silence.pending", "id", &silenceID),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceAlertmanagerSilenceInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceAlertmanagerSilencePending("2024-01-01T00:00:00Z", "2024-01-01T01:00:00Z"),
+				ExpectError: regexp.MustCompile(`Silence already ended`),
+			},
+			{
+				Config:      testAccResourceAlertmanagerSilenceInvalidTimes,
+				ExpectError: regexp.MustCompile(`Exactly one of ends_at or duration must be set`),
+			},
+			{
+				Config:      testAccResourceAlertmanagerSilenceInvalidMatcher,
+				ExpectError: regexp.MustCompile(`"team-name" is not a valid label name`),
+			},
+		},
+	})
+}
+
+func testAccResourceAlertmanagerSilence(comment string) string {
+	return `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_alertmanager_silence" "demo" {
+  matchers = [
+    { name = "cluster", value = "eu-west-1" },
+    { name = "alertname", value = "Node.*", is_regex = true },
+  ]
+  duration   = "2h"
+  comment    = "` + comment + `"
+  created_by = "terraform"
+}
+`
+}
+
+func testAccResourceAlertmanagerSilencePending(startsAt, endsAt string) string {
+	return `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_alertmanager_silence" "pending" {
+  matchers   = [{ name = "cluster", value = "eu-west-1" }]
+  starts_at  = "` + startsAt + `"
+  ends_at    = "` + endsAt + `"
+  comment    = "Upgrade"
+  created_by = "terraform"
+}
+`
+}
+
+const testAccResourceAlertmanagerSilenceInvalidTimes = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_alertmanager_silence" "invalid" {
+  matchers   = [{ name = "cluster", value = "eu-west-1" }]
+  ends_at    = "2024-01-01T01:00:00Z"
+  duration   = "1h"
+  comment    = "Upgrade"
+  created_by = "terraform"
+}
+`
+
+const testAccResourceAlertmanagerSilenceInvalidMatcher = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_alertmanager_silence" "invalid" {
+  matchers   = [{ name = "team-name", value = "a" }]
+  duration   = "1h"
+  comment    = "Upgrade"
+  created_by = "terraform"
+}
+`
@@ -0,0 +1,282 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/prometheus/alertmanager/template"
+	amtypes "github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AlertmanagerTemplatePreviewDataSource{}
+
+func NewAlertmanagerTemplatePreviewDataSource() datasource.DataSource {
+	return &AlertmanagerTemplatePreviewDataSource{}
+}
+
+// AlertmanagerTemplatePreviewDataSource renders a notification template with
+// sample alerts. It does not call Mimir.
+type AlertmanagerTemplatePreviewDataSource struct{}
+
+// AlertmanagerTemplatePreviewDataSourceModel describes the data source data model.
+type AlertmanagerTemplatePreviewDataSourceModel struct {
+	ID                  types.String                `tfsdk:"id"`
+	TemplatesConfigYAML types.Map                   `tfsdk:"templates_config_yaml"`
+	Template            types.String                `tfsdk:"template"`
+	HTML                types.Bool                  `tfsdk:"html"`
+	Receiver            types.String                `tfsdk:"receiver"`
+	GroupLabels         types.Map                   `tfsdk:"group_labels"`
+	ExternalURL         types.String                `tfsdk:"external_url"`
+	Alerts              []templatePreviewAlertModel `tfsdk:"alerts"`
+	Rendered            types.String                `tfsdk:"rendered"`
+}
+
+type templatePreviewAlertModel struct {
+	Status       types.String `tfsdk:"status"`
+	Labels       types.Map    `tfsdk:"labels"`
+	Annotations  types.Map    `tfsdk:"annotations"`
+	StartsAt     types.String `tfsdk:"starts_at"`
+	EndsAt       types.String `tfsdk:"ends_at"`
+	GeneratorURL types.String `tfsdk:"generator_url"`
+}
+
+func (d *AlertmanagerTemplatePreviewDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alertmanager_template_preview"
+}
+
+func (d *AlertmanagerTemplatePreviewDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Renders a notification template with sample alerts, using the Alertmanager template engine. The template is rendered locally, without calling Grafana Mimir.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "hash",
+			},
+			"templates_config_yaml": schema.MapAttribute{
+				MarkdownDescription: "A map of template names to template content, as in the `templates_config_yaml` attribute of `mimirtool_alertmanager`.",
+				ElementType:         types.StringType,
+				Required:            true,
+				Validators: []validator.Map{
+					alertmanagerTemplatesValidator{},
+				},
+			},
+			"template": schema.StringAttribute{
+				MarkdownDescription: "The name of the template to render, as given to `define`.",
+				Required:            true,
+			},
+			"html": schema.BoolAttribute{
+				MarkdownDescription: "Render the template with the HTML template engine, which escapes the values, as for the `html` of email notifications. Defaults to `false`.",
+				Optional:            true,
+			},
+			"receiver": schema.StringAttribute{
+				MarkdownDescription: "The name of the receiver the notification is sent to. Defaults to `preview`.",
+				Optional:            true,
+			},
+			"group_labels": schema.MapAttribute{
+				MarkdownDescription: "The labels the alerts are grouped by.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"external_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the Alertmanager, available as `.ExternalURL`.",
+				Optional:            true,
+			},
+			"alerts": schema.ListNestedAttribute{
+				MarkdownDescription: "The sample alerts of the notification.",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"status": schema.StringAttribute{
+							MarkdownDescription: "The status of the alert: `firing` or `resolved`. Defaults to `firing`.",
+							Optional:            true,
+						},
+						"labels": schema.MapAttribute{
+							MarkdownDescription: "The labels of the alert.",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
+						"annotations": schema.MapAttribute{
+							MarkdownDescription: "The annotations of the alert.",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
+						"starts_at": schema.StringAttribute{
+							MarkdownDescription: "When the alert started firing, in RFC 3339 format. Defaults to the current time.",
+							Optional:            true,
+						},
+						"ends_at": schema.StringAttribute{
+							MarkdownDescription: "When the alert was resolved, in RFC 3339 format. Defaults to the current time for resolved alerts.",
+							Optional:            true,
+						},
+						"generator_url": schema.StringAttribute{
+							MarkdownDescription: "The URL of the rule which generated the alert.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"rendered": schema.StringAttribute{
+				MarkdownDescription: "The rendered template.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *AlertmanagerTemplatePreviewDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AlertmanagerTemplatePreviewDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tmpl, err := template.New()
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating template", err.Error())
+		return
+	}
+	// Only used to look up the defined templates, the Alertmanager one doesn't expose them
+	definitions := texttemplate.New("").Funcs(texttemplate.FuncMap(template.DefaultFuncs))
+	templates := mapStringFromTypesMap(data.TemplatesConfigYAML)
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	// Definitions of the same template override each other in this order
+	sort.Strings(names)
+	for _, name := range names {
+		err := tmpl.Parse(strings.NewReader(templates[name]))
+		if err == nil {
+			_, err = definitions.Parse(templates[name])
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("templates_config_yaml").AtMapKey(name),
+				"Invalid Alertmanager template",
+				fmt.Sprintf("Template %q is not valid: %s", name, err.Error()),
+			)
+			return
+		}
+	}
+	if definitions.Lookup(data.Template.ValueString()) == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("template"),
+			"Missing Alertmanager template",
+			fmt.Sprintf("Template %q is not defined by templates_config_yaml.", data.Template.ValueString()),
+		)
+		return
+	}
+
+	tmpl.ExternalURL = &url.URL{}
+	if !data.ExternalURL.IsNull() {
+		if tmpl.ExternalURL, err = url.Parse(data.ExternalURL.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("external_url"),
+				"Invalid URL",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	alerts, diags := templatePreviewAlerts(data.Alerts, time.Now())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	receiver := "preview"
+	if !data.Receiver.IsNull() {
+		receiver = data.Receiver.ValueString()
+	}
+	groupLabels := model.LabelSet{}
+	for name, value := range mapStringFromTypesMap(data.GroupLabels) {
+		groupLabels[model.LabelName(name)] = model.LabelValue(value)
+	}
+	templateData := tmpl.Data(receiver, groupLabels, alerts...)
+
+	text := fmt.Sprintf("{{ template %q . }}", data.Template.ValueString())
+	var rendered string
+	if data.HTML.ValueBool() {
+		rendered, err = tmpl.ExecuteHTMLString(text, templateData)
+	} else {
+		rendered, err = tmpl.ExecuteTextString(text, templateData)
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("template"),
+			"Error rendering template",
+			fmt.Sprintf("Could not render template %q: %s", data.Template.ValueString(), err.Error()),
+		)
+		return
+	}
+
+	data.Rendered = types.StringValue(rendered)
+	data.ID = types.StringValue(hash(rendered))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// templatePreviewAlerts converts the sample alerts to Alertmanager alerts.
+func templatePreviewAlerts(models []templatePreviewAlertModel, now time.Time) ([]*amtypes.Alert, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	alerts := make([]*amtypes.Alert, 0, len(models))
+	for i, m := range models {
+		alertPath := path.Root("alerts").AtListIndex(i)
+		alert := &amtypes.Alert{
+			Alert: model.Alert{
+				Labels:       model.LabelSet{},
+				Annotations:  model.LabelSet{},
+				StartsAt:     now,
+				GeneratorURL: m.GeneratorURL.ValueString(),
+			},
+		}
+		for name, value := range mapStringFromTypesMap(m.Labels) {
+			alert.Labels[model.LabelName(name)] = model.LabelValue(value)
+		}
+		for name, value := range mapStringFromTypesMap(m.Annotations) {
+			alert.Annotations[model.LabelName(name)] = model.LabelValue(value)
+		}
+
+		var err error
+		if !m.StartsAt.IsNull() {
+			if alert.StartsAt, err = time.Parse(time.RFC3339, m.StartsAt.ValueString()); err != nil {
+				diags.AddAttributeError(alertPath.AtName("starts_at"), "Invalid time", err.Error())
+				continue
+			}
+		}
+		if !m.EndsAt.IsNull() {
+			if alert.EndsAt, err = time.Parse(time.RFC3339, m.EndsAt.ValueString()); err != nil {
+				diags.AddAttributeError(alertPath.AtName("ends_at"), "Invalid time", err.Error())
+				continue
+			}
+		}
+
+		// The status of the alerts is computed from their end time
+		status := model.AlertStatus(m.Status.ValueString())
+		resolved := status == model.AlertResolved
+		switch {
+		case !m.Status.IsNull() && status != model.AlertFiring && !resolved:
+			diags.AddAttributeError(alertPath.AtName("status"), "Invalid alert", fmt.Sprintf("The status must be %q or %q.", model.AlertFiring, model.AlertResolved))
+		case resolved && alert.EndsAt.IsZero():
+			alert.EndsAt = now
+		case resolved && alert.EndsAt.After(now):
+			diags.AddAttributeError(alertPath.AtName("ends_at"), "Invalid alert", "A resolved alert can't end in the future.")
+		case !resolved && !alert.EndsAt.IsZero() && !alert.EndsAt.After(now):
+			diags.AddAttributeError(alertPath.AtName("ends_at"), "Invalid alert", "A firing alert can't end in the past, set status to \"resolved\".")
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, diags
+}
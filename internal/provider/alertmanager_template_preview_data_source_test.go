@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceAlertmanagerTemplatePreview(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAlertmanagerTemplatePreview,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_template_preview.title", "rendered", "[FIRING:1] HighErrorRate (api)"),
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_template_preview.text", "rendered", "firing: Error rate <5%> on api since 2024-01-02T03:04:05Z\nresolved: Error rate <5%> on web\nhttps://alertmanager.example.org/#/alerts?receiver=slack"),
+					resource.TestCheckResourceAttr("data.mimirtool_alertmanager_template_preview.html", "rendered", "firing: Error rate &lt;5%&gt; on api since 2024-01-02T03:04:05Z\nresolved: Error rate &lt;5%&gt; on web\nhttps://alertmanager.example.org/#/alerts?receiver=slack"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceAlertmanagerTemplatePreviewErrors(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceAlertmanagerTemplatePreviewMissing,
+				ExpectError: regexp.MustCompile(`Template "slack.missing" is not defined`),
+			},
+			{
+				Config:      testAccDataSourceAlertmanagerTemplatePreviewStatus,
+				ExpectError: regexp.MustCompile(`The status must be "firing" or "resolved"`),
+			},
+		},
+	})
+}
+
+func TestTemplatePreviewAlerts(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	alerts, diags := templatePreviewAlerts([]templatePreviewAlertModel{
+		{},
+		{Status: types.StringValue("resolved")},
+		{Status: types.StringValue("resolved"), EndsAt: types.StringValue("2024-01-02T00:00:00Z")},
+	}, now)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	for i, expected := range []string{"firing", "resolved", "resolved"} {
+		if status := string(alerts[i].StatusAt(now)); status != expected {
+			t.Errorf("alert %d: expected status %s, got %s", i, expected, status)
+		}
+	}
+
+	for _, alert := range []templatePreviewAlertModel{
+		{Status: types.StringValue("pending")},
+		{Status: types.StringValue("resolved"), EndsAt: types.StringValue("2024-01-03T00:00:00Z")},
+		{EndsAt: types.StringValue("2024-01-02T00:00:00Z")},
+		{StartsAt: types.StringValue("yesterday")},
+	} {
+		if _, diags := templatePreviewAlerts([]templatePreviewAlertModel{alert}, now); !diags.HasError() {
+			t.Errorf("expected an error for alert %+v", alert)
+		}
+	}
+}
+
+const testAccDataSourceAlertmanagerTemplatePreviewTemplates = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+locals {
+  templates = {
+    "slack.tmpl" = <<EOT
+{{ define "slack.title" }}[{{ .Status | toUpper }}:{{ .Alerts.Firing | len }}] {{ .CommonLabels.alertname }} ({{ .GroupLabels.service }}){{ end }}
+{{ define "slack.text" }}{{ range .Alerts }}{{ .Status }}: {{ .Annotations.summary }}{{ if eq .Status "firing" }} since {{ .StartsAt.Format "2006-01-02T15:04:05Z07:00" }}{{ end }}
+{{ end }}{{ template "__alertmanagerURL" . }}{{ end }}
+EOT
+    "default.tmpl" = file("testdata/example_alertmanager_template.tmpl")
+  }
+}
+`
+
+const testAccDataSourceAlertmanagerTemplatePreview = testAccDataSourceAlertmanagerTemplatePreviewTemplates + `
+data "mimirtool_alertmanager_template_preview" "title" {
+  templates_config_yaml = local.templates
+  template              = "slack.title"
+  group_labels          = { service = "api" }
+  alerts = [
+    { labels = { alertname = "HighErrorRate", service = "api" } },
+  ]
+}
+
+data "mimirtool_alertmanager_template_preview" "text" {
+  templates_config_yaml = local.templates
+  template              = "slack.text"
+  receiver              = "slack"
+  external_url          = "https://alertmanager.example.org"
+  alerts = [
+    {
+      labels      = { alertname = "HighErrorRate", service = "api" }
+      annotations = { summary = "Error rate <5%> on api" }
+      starts_at   = "2024-01-02T03:04:05Z"
+    },
+    {
+      status      = "resolved"
+      labels      = { alertname = "HighErrorRate", service = "web" }
+      annotations = { summary = "Error rate <5%> on web" }
+    },
+  ]
+}
+
+data "mimirtool_alertmanager_template_preview" "html" {
+  templates_config_yaml = local.templates
+  template              = "slack.text"
+  html                  = true
+  receiver              = "slack"
+  external_url          = "https://alertmanager.example.org"
+  alerts                = data.mimirtool_alertmanager_template_preview.text.alerts
+}
+`
+
+const testAccDataSourceAlertmanagerTemplatePreviewMissing = testAccDataSourceAlertmanagerTemplatePreviewTemplates + `
+data "mimirtool_alertmanager_template_preview" "missing" {
+  templates_config_yaml = local.templates
+  template              = "slack.missing"
+  alerts                = [{}]
+}
+`
+
+const testAccDataSourceAlertmanagerTemplatePreviewStatus = testAccDataSourceAlertmanagerTemplatePreviewTemplates + `
+data "mimirtool_alertmanager_template_preview" "status" {
+  templates_config_yaml = local.templates
+  template              = "slack.title"
+  alerts                = [{ status = "pending" }]
+}
+`
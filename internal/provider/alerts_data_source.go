@@ -0,0 +1,291 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	amlabels "github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	alertsSourceRuler        = "ruler"
+	alertsSourceAlertmanager = "alertmanager"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource                   = &AlertsDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &AlertsDataSource{}
+)
+
+func NewAlertsDataSource() datasource.DataSource {
+	return &AlertsDataSource{}
+}
+
+// AlertsDataSource lists the active alerts of a tenant, as evaluated by the
+// ruler and as received by Alertmanager.
+type AlertsDataSource struct {
+	client mimirClientInterface
+}
+
+// AlertsDataSourceModel describes the data source data model.
+type AlertsDataSourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	TenantID types.String `tfsdk:"tenant_id"`
+	Source   types.String `tfsdk:"source"`
+	Matchers types.List   `tfsdk:"matchers"`
+	State    types.String `tfsdk:"state"`
+	Alerts   []alertModel `tfsdk:"alerts"`
+}
+
+type alertModel struct {
+	Source      types.String `tfsdk:"source"`
+	Labels      types.Map    `tfsdk:"labels"`
+	Annotations types.Map    `tfsdk:"annotations"`
+	State       types.String `tfsdk:"state"`
+	ActiveAt    types.String `tfsdk:"active_at"`
+}
+
+func (d *AlertsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alerts"
+}
+
+func (d *AlertsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the active alerts of a tenant, as evaluated by the ruler and as received by Alertmanager. " +
+			"[Official documentation](https://grafana.com/docs/mimir/latest/references/http-api/#get-alerts)",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "hash",
+			},
+			"tenant_id": schema.StringAttribute{
+				MarkdownDescription: "Tenant ID to list the alerts of. Overrides the provider `tenant_id`.",
+				Optional:            true,
+			},
+			"source": schema.StringAttribute{
+				MarkdownDescription: "Only list the alerts of this source: `ruler` for the pending and firing alerts of the ruler, `alertmanager` for the alerts received by Alertmanager. Both sources are listed when not set. A source which can't be reached, e.g. Alertmanager not configured for the tenant, fails the read rather than listing no alerts.",
+				Optional:            true,
+			},
+			"matchers": schema.ListAttribute{
+				MarkdownDescription: "Only list the alerts matching all these matchers, in the Alertmanager syntax such as `severity=\"critical\"` or `team=~\"a|b\"`.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"state": schema.StringAttribute{
+				MarkdownDescription: "Only list the alerts in this state: `pending` or `firing` for the ruler, `active`, `suppressed` or `unprocessed` for Alertmanager.",
+				Optional:            true,
+			},
+			"alerts": schema.ListNestedAttribute{
+				MarkdownDescription: "The alerts, those of the ruler first, sorted by labels.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"source": schema.StringAttribute{
+							MarkdownDescription: "The source of the alert: `ruler` or `alertmanager`.",
+							Computed:            true,
+						},
+						"labels": schema.MapAttribute{
+							MarkdownDescription: "The labels of the alert.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"annotations": schema.MapAttribute{
+							MarkdownDescription: "The annotations of the alert.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"state": schema.StringAttribute{
+							MarkdownDescription: "The state of the alert in its source.",
+							Computed:            true,
+						},
+						"active_at": schema.StringAttribute{
+							MarkdownDescription: "When the alert became active, in RFC 3339 format.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AlertsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(mimirClientInterface)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected mimirClientInterface, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+// ValidateConfig checks the source and the matchers.
+func (d *AlertsDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config AlertsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Source.IsNull() && !config.Source.IsUnknown() {
+		if source := config.Source.ValueString(); source != alertsSourceRuler && source != alertsSourceAlertmanager {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("source"),
+				"Invalid alerts source",
+				fmt.Sprintf("The source must be %q or %q, got %q.", alertsSourceRuler, alertsSourceAlertmanager, source),
+			)
+		}
+	}
+	if isFullyKnown(ctx, config.Matchers) {
+		parseAlertMatchers(ctx, config.Matchers, &resp.Diagnostics)
+	}
+}
+
+func (d *AlertsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AlertsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	matchers := parseAlertMatchers(ctx, data.Matchers, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	cli := tenantClient(d.client, data.TenantID)
+	source := data.Source.ValueString()
+
+	var ruler, alertmanager []alertModel
+	if source == "" || source == alertsSourceRuler {
+		alerts, err := cli.ListRulerAlerts(ctx)
+		if err != nil {
+			detail := fmt.Sprintf("Could not list the alerts of the ruler: %s", err.Error())
+			if errors.Is(err, client.ErrResourceNotFound) {
+				detail += "\n\nThe ruler API was not found, check the provider `prometheus_http_prefix`."
+			}
+			resp.Diagnostics.AddError("Error Reading Mimir ruler alerts", detail)
+			return
+		}
+		for _, alert := range alerts {
+			var activeAt time.Time
+			if alert.ActiveAt != nil {
+				activeAt = *alert.ActiveAt
+			}
+			ruler = append(ruler, newAlertModel(alertsSourceRuler, alert.Labels, alert.Annotations, alert.State, activeAt))
+		}
+	}
+	if source == "" || source == alertsSourceAlertmanager {
+		alerts, err := cli.ListAlertmanagerAlerts(ctx)
+		if err != nil {
+			detail := fmt.Sprintf("Could not list the alerts of Alertmanager: %s", err.Error())
+			// Mimir also answers 404 when Alertmanager is not configured for the tenant
+			if errors.Is(err, client.ErrResourceNotFound) {
+				detail += "\n\nThe Alertmanager API was not found, check the provider `alertmanager_http_prefix` and that Alertmanager is configured for the tenant, " +
+					"or set `source = \"" + alertsSourceRuler + "\"` to only list the alerts of the ruler."
+			}
+			resp.Diagnostics.AddError("Error Reading Alertmanager alerts", detail)
+			return
+		}
+		for _, alert := range alerts {
+			alertmanager = append(alertmanager, newAlertModel(alertsSourceAlertmanager, alert.Labels, alert.Annotations, alert.Status.State, alert.StartsAt))
+		}
+	}
+
+	data.Alerts = []alertModel{}
+	for _, alerts := range [][]alertModel{ruler, alertmanager} {
+		sort.SliceStable(alerts, func(i, j int) bool {
+			return alertLabels(alerts[i]).String() < alertLabels(alerts[j]).String()
+		})
+		for _, alert := range alerts {
+			if !data.State.IsNull() && alert.State.ValueString() != data.State.ValueString() {
+				continue
+			}
+			if !alertMatches(matchers, alertLabels(alert)) {
+				continue
+			}
+			data.Alerts = append(data.Alerts, alert)
+		}
+	}
+
+	var matcherStrings []string
+	for _, m := range matchers {
+		matcherStrings = append(matcherStrings, m.String())
+	}
+	data.ID = types.StringValue(hash(strings.Join([]string{data.TenantID.ValueString(), source, data.State.ValueString(), strings.Join(matcherStrings, ",")}, "/")))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func newAlertModel(source string, labels, annotations map[string]string, state string, activeAt time.Time) alertModel {
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	alert := alertModel{
+		Source:      types.StringValue(source),
+		Labels:      typeMapFromMapString(labels),
+		Annotations: typeMapFromMapString(annotations),
+		State:       types.StringValue(state),
+		ActiveAt:    types.StringNull(),
+	}
+	if !activeAt.IsZero() {
+		alert.ActiveAt = types.StringValue(activeAt.UTC().Format(time.RFC3339))
+	}
+	return alert
+}
+
+func alertLabels(alert alertModel) model.LabelSet {
+	labels := model.LabelSet{}
+	for name, value := range mapStringFromTypesMap(alert.Labels) {
+		labels[model.LabelName(name)] = model.LabelValue(value)
+	}
+	return labels
+}
+
+// alertMatches reports whether the labels match all the matchers.
+func alertMatches(matchers amlabels.Matchers, labels model.LabelSet) bool {
+	for _, m := range matchers {
+		if !m.Matches(string(labels[model.LabelName(m.Name)])) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAlertMatchers parses the matchers of the list, in the Alertmanager syntax.
+func parseAlertMatchers(ctx context.Context, list types.List, diagnostics *diag.Diagnostics) amlabels.Matchers {
+	if list.IsNull() || list.IsUnknown() {
+		return nil
+	}
+	var values []string
+	diagnostics.Append(list.ElementsAs(ctx, &values, false)...)
+	matchers := make(amlabels.Matchers, 0, len(values))
+	for i, value := range values {
+		m, err := amlabels.ParseMatcher(value)
+		if err != nil {
+			diagnostics.AddAttributeError(
+				path.Root("matchers").AtListIndex(i),
+				"Invalid matcher",
+				fmt.Sprintf("%q is not a valid matcher: %s", value, err.Error()),
+			)
+			continue
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers
+}
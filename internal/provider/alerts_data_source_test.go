@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceAlerts(t *testing.T) {
+	server := newTestMimirServer(t, "/prometheus", "/alertmanager")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					payload, err := json.Marshal([]alertmanagerAlert{
+						{Labels: map[string]string{"alertname": "HighErrorRate", "severity": "critical", "service": "api"}, Annotations: map[string]string{"summary": "Error rate above 5%"}},
+						{Labels: map[string]string{"alertname": "HighLatency", "severity": "warning", "service": "api"}},
+						{Labels: map[string]string{"alertname": "HighErrorRate", "severity": "critical", "service": "web"}},
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					resp, err := http.Post(server.URL+"/alertmanager/api/v2/alerts", "application/json", bytes.NewReader(payload))
+					if err != nil {
+						t.Fatal(err)
+					}
+					resp.Body.Close()
+				},
+				Config: fmt.Sprintf(testAccDataSourceAlerts, server.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.all", "alerts.#", "5"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.all", "alerts.0.source", "ruler"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.all", "alerts.0.labels.alertname", "DiskFull"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.all", "alerts.0.state", "firing"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.all", "alerts.0.active_at", "2024-01-02T03:04:05Z"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.all", "alerts.0.annotations.summary", "Disk is full"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.all", "alerts.1.labels.alertname", "InstanceDown"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.all", "alerts.1.state", "pending"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.all", "alerts.2.source", "alertmanager"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.all", "alerts.2.labels.service", "api"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.all", "alerts.2.annotations.summary", "Error rate above 5%"),
+					resource.TestCheckResourceAttrSet("data.mimirtool_alerts.all", "alerts.2.active_at"),
+
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.critical", "alerts.#", "1"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.critical", "alerts.0.labels.service", "api"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.critical", "alerts.0.state", "active"),
+
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.silenced", "alerts.#", "1"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.silenced", "alerts.0.labels.service", "web"),
+
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.firing", "alerts.#", "1"),
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.firing", "alerts.0.labels.alertname", "DiskFull"),
+
+					resource.TestCheckResourceAttr("data.mimirtool_alerts.other_tenant", "alerts.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceAlertsInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceAlertsInvalidMatcher,
+				ExpectError: regexp.MustCompile(`Invalid matcher`),
+			},
+			{
+				Config:      testAccDataSourceAlertsInvalidSource,
+				ExpectError: regexp.MustCompile(`The source must be "ruler" or "alertmanager"`),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceAlertsNotFound(t *testing.T) {
+	server := newTestMimirServer(t, "/prometheus", "/alertmanager")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// A misrouted source is not read as a source without alerts
+				Config:      fmt.Sprintf(testAccDataSourceAlertsPrefixes, server.URL, "/custom/prometheus", "/alertmanager", "ruler"),
+				ExpectError: regexp.MustCompile(`ruler API was not found, check the provider\s+` + "`prometheus_http_prefix`"),
+			},
+			{
+				Config:      fmt.Sprintf(testAccDataSourceAlertsPrefixes, server.URL, "/prometheus", "/custom/alertmanager", "alertmanager"),
+				ExpectError: regexp.MustCompile(`Alertmanager API was not found, check the provider\s+` + "`alertmanager_http_prefix`"),
+			},
+		},
+	})
+}
+
+const testAccDataSourceAlerts = `
+provider "mimirtool" {
+  address = %q
+}
+
+resource "mimirtool_ruler_namespace" "alerts" {
+  namespace   = "alerts"
+  config_yaml = <<EOT
+groups:
+  - name: alerts
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+        for: 5m
+        labels:
+          severity: critical
+      - alert: DiskFull
+        expr: node_filesystem_avail_bytes == 0
+        labels:
+          severity: warning
+        annotations:
+          summary: Disk is full
+      - record: job:up:sum
+        expr: sum by (job) (up)
+EOT
+}
+
+resource "mimirtool_alertmanager_silence" "web" {
+  matchers   = [{ name = "service", value = "web" }]
+  duration   = "1h"
+  comment    = "Web maintenance"
+  created_by = "terraform"
+}
+
+data "mimirtool_alerts" "all" {
+  depends_on = [mimirtool_ruler_namespace.alerts, mimirtool_alertmanager_silence.web]
+}
+
+data "mimirtool_alerts" "critical" {
+  source   = "alertmanager"
+  matchers = ["severity=\"critical\""]
+  state    = "active"
+
+  depends_on = [mimirtool_ruler_namespace.alerts, mimirtool_alertmanager_silence.web]
+}
+
+data "mimirtool_alerts" "silenced" {
+  source = "alertmanager"
+  state  = "suppressed"
+
+  depends_on = [mimirtool_ruler_namespace.alerts, mimirtool_alertmanager_silence.web]
+}
+
+data "mimirtool_alerts" "firing" {
+  source = "ruler"
+  state  = "firing"
+
+  depends_on = [mimirtool_ruler_namespace.alerts, mimirtool_alertmanager_silence.web]
+}
+
+data "mimirtool_alerts" "other_tenant" {
+  tenant_id = "tenant-a"
+
+  depends_on = [mimirtool_ruler_namespace.alerts, mimirtool_alertmanager_silence.web]
+}
+`
+
+const testAccDataSourceAlertsInvalidMatcher = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+data "mimirtool_alerts" "invalid" {
+  matchers = ["severity=~(critical"]
+}
+`
+
+const testAccDataSourceAlertsInvalidSource = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+data "mimirtool_alerts" "invalid" {
+  source = "prometheus"
+}
+`
+
+const testAccDataSourceAlertsPrefixes = `
+provider "mimirtool" {
+  address                  = %q
+  prometheus_http_prefix   = %q
+  alertmanager_http_prefix = %q
+}
+
+data "mimirtool_alerts" "misrouted" {
+  source = %q
+}
+`
@@ -0,0 +1,399 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/grafana/dskit/crypto/tls"
+	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Ruler configuration API, served under the Prometheus HTTP prefix
+	rulerConfigAPIPath = "/config/v1/rules"
+	// Alertmanager configuration API, Mimir always serves it at the root
+	alertmanagerConfigAPIPath = "/api/v1/alerts"
+	// Prometheus API of the ruler, served under the Prometheus HTTP prefix
+	prometheusAPIPath = "/api/v1"
+	// Alertmanager API, served under the Alertmanager HTTP prefix
+	alertmanagerAPIPath = "/api/v2"
+)
+
+// Ensure mimirClient satisfies the interface used by the resources.
+var _ mimirClientInterface = &mimirClient{}
+
+// mimirClient is the HTTP client used by the provider to talk to Grafana Mimir.
+// Unlike the mimirtool client, it routes the ruler calls under the configured
+// Prometheus HTTP prefix and the Alertmanager API calls under the configured
+// Alertmanager HTTP prefix. The Alertmanager configuration API is not affected
+// by the Alertmanager HTTP prefix as Mimir always serves it at /api/v1/alerts.
+type mimirClient struct {
+	endpoint               *url.URL
+	httpClient             *http.Client
+	userAgent              string
+	tenantID               string
+	apiUser                string
+	apiKey                 string
+	authToken              string
+	prometheusHTTPPrefix   string
+	alertmanagerHTTPPrefix string
+}
+
+// alertmanagerConfig is the payload of the Alertmanager configuration API.
+type alertmanagerConfig struct {
+	TemplateFiles      map[string]string `yaml:"template_files"`
+	AlertmanagerConfig string            `yaml:"alertmanager_config"`
+}
+
+func newMimirClient(cfg MimirClientConfig, version string) (*mimirClient, error) {
+	endpoint, err := url.Parse(cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", cfg.Address, err)
+	}
+
+	if (cfg.APIUser != "" || cfg.APIKey != "") && cfg.AuthToken != "" {
+		return nil, errors.New("at most one of basic auth (api_user/api_key) or auth_token should be configured")
+	}
+
+	tlsClientConfig := tls.ClientConfig{
+		CAPath:             cfg.TLSCAPath,
+		CertPath:           cfg.TLSCertPath,
+		KeyPath:            cfg.TLSKeyPath,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	tlsConfig, err := tlsClientConfig.GetTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS configuration: %w", err)
+	}
+
+	httpClient := &http.Client{}
+	if tlsConfig != nil {
+		httpClient.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}
+	}
+
+	// Like the mimirtool client, use the tenant as basic auth user when only
+	// the API key is set. It is resolved once so that overriding the tenant of
+	// a resource does not change the credentials.
+	apiUser := cfg.APIUser
+	if apiUser == "" && cfg.APIKey != "" {
+		apiUser = cfg.TenantID
+	}
+
+	return &mimirClient{
+		endpoint:               endpoint,
+		httpClient:             httpClient,
+		userAgent:              fmt.Sprintf("terraform-provider-mimirtool/%s", version),
+		tenantID:               cfg.TenantID,
+		apiUser:                apiUser,
+		apiKey:                 cfg.APIKey,
+		authToken:              cfg.AuthToken,
+		prometheusHTTPPrefix:   cfg.PrometheusHTTPPrefix,
+		alertmanagerHTTPPrefix: cfg.AlertmanagerHTTPPrefix,
+	}, nil
+}
+
+// WithTenantID returns a copy of the client sending requests on behalf of the
+// given tenant, or the client itself when tenantID is empty. Only the
+// X-Scope-OrgID header changes, the credentials are kept.
+func (c *mimirClient) WithTenantID(tenantID string) mimirClientInterface {
+	if tenantID == "" {
+		return c
+	}
+	tenantClient := *c
+	tenantClient.tenantID = tenantID
+	return &tenantClient
+}
+
+// rulerURL returns the URL of the ruler configuration API for the given
+// path elements (namespace, group), which are escaped.
+func (c *mimirClient) rulerURL(elem ...string) *url.URL {
+	return c.endpoint.JoinPath(append([]string{c.prometheusHTTPPrefix, rulerConfigAPIPath}, escapePathElements(elem)...)...)
+}
+
+// prometheusURL returns the URL of the Prometheus API for the given path
+// elements, which are escaped.
+func (c *mimirClient) prometheusURL(elem ...string) *url.URL {
+	return c.endpoint.JoinPath(append([]string{c.prometheusHTTPPrefix, prometheusAPIPath}, escapePathElements(elem)...)...)
+}
+
+// alertmanagerURL returns the URL of the Alertmanager API for the given path
+// elements, which are escaped.
+func (c *mimirClient) alertmanagerURL(elem ...string) *url.URL {
+	return c.endpoint.JoinPath(append([]string{c.alertmanagerHTTPPrefix, alertmanagerAPIPath}, escapePathElements(elem)...)...)
+}
+
+func escapePathElements(elem []string) []string {
+	escaped := make([]string, 0, len(elem))
+	for _, e := range elem {
+		escaped = append(escaped, url.PathEscape(e))
+	}
+	return escaped
+}
+
+// doRequest sends the request with a YAML payload and returns the response body.
+// A 404 response is reported as client.ErrResourceNotFound, any other non 2xx
+// response as an error holding the status and the beginning of the body.
+func (c *mimirClient) doRequest(ctx context.Context, method string, u *url.URL, payload []byte) ([]byte, error) {
+	return c.doRequestWithContentType(ctx, method, u, "application/yaml", payload)
+}
+
+// doJSONRequest sends the request with the JSON encoding of in as payload, when
+// not nil, and decodes the JSON response body into out, when not nil.
+func (c *mimirClient) doJSONRequest(ctx context.Context, method string, u *url.URL, in interface{}, out interface{}) error {
+	var payload []byte
+	if in != nil {
+		var err error
+		if payload, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	body, err := c.doRequestWithContentType(ctx, method, u, "application/json", payload)
+	if err != nil || out == nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("unable to unmarshal response of %s request to %s: %w", method, u, err)
+	}
+	return nil
+}
+
+// doPrometheusRequest sends a GET request to the Prometheus API and decodes the
+// data of the response into out.
+func (c *mimirClient) doPrometheusRequest(ctx context.Context, u *url.URL, out interface{}) error {
+	var resp struct {
+		Status    string          `json:"status"`
+		Data      json.RawMessage `json:"data"`
+		ErrorType string          `json:"errorType"`
+		Error     string          `json:"error"`
+	}
+	if err := c.doJSONRequest(ctx, http.MethodGet, u, nil, &resp); err != nil {
+		return err
+	}
+	if resp.Status != "success" {
+		return fmt.Errorf("request to %s failed: %s: %s", u, resp.ErrorType, resp.Error)
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("unable to unmarshal data of the response to %s: %w", u, err)
+	}
+	return nil
+}
+
+func (c *mimirClient) doRequestWithContentType(ctx context.Context, method string, u *url.URL, contentType string, payload []byte) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", c.tenantID)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	switch {
+	case c.apiUser != "" || c.apiKey != "":
+		req.SetBasicAuth(c.apiUser, c.apiKey)
+	case c.authToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+
+	tflog.Debug(ctx, "Sending request to Grafana Mimir", map[string]interface{}{"method": method, "url": u.String()})
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s request to %s failed: reading body: %w", method, u, err)
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return respBody, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%s request to %s failed: %w", method, u, client.ErrResourceNotFound)
+	case len(respBody) == 0:
+		return nil, fmt.Errorf("%s request to %s failed: server returned HTTP status: %s", method, u, resp.Status)
+	default:
+		if len(respBody) > 1024 {
+			respBody = respBody[:1024]
+		}
+		return nil, fmt.Errorf("%s request to %s failed: server returned HTTP status: %s, body: %q", method, u, resp.Status, respBody)
+	}
+}
+
+// CreateRuleGroup creates or replaces a rule group in the namespace.
+func (c *mimirClient) CreateRuleGroup(ctx context.Context, namespace string, rg rwrulefmt.RuleGroup) error {
+	payload, err := yaml.Marshal(&rg)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(ctx, http.MethodPost, c.rulerURL(namespace), payload)
+	return err
+}
+
+// DeleteRuleGroup deletes a rule group from the namespace.
+func (c *mimirClient) DeleteRuleGroup(ctx context.Context, namespace string, groupName string) error {
+	_, err := c.doRequest(ctx, http.MethodDelete, c.rulerURL(namespace, groupName), nil)
+	return err
+}
+
+// GetRuleGroup returns a rule group of the namespace.
+func (c *mimirClient) GetRuleGroup(ctx context.Context, namespace string, groupName string) (*rwrulefmt.RuleGroup, error) {
+	body, err := c.doRequest(ctx, http.MethodGet, c.rulerURL(namespace, groupName), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var rg rwrulefmt.RuleGroup
+	if err := yaml.Unmarshal(body, &rg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal rule group: %w", err)
+	}
+	return &rg, nil
+}
+
+// ListRules returns the rule groups of the namespace, or of every namespace
+// of the tenant when namespace is empty, indexed by namespace.
+func (c *mimirClient) ListRules(ctx context.Context, namespace string) (map[string][]rwrulefmt.RuleGroup, error) {
+	u := c.rulerURL()
+	if namespace != "" {
+		u = c.rulerURL(namespace)
+	}
+	body, err := c.doRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleSet := map[string][]rwrulefmt.RuleGroup{}
+	if err := yaml.Unmarshal(body, &ruleSet); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal rule groups: %w", err)
+	}
+	return ruleSet, nil
+}
+
+// DeleteNamespace deletes every rule group of the namespace.
+func (c *mimirClient) DeleteNamespace(ctx context.Context, namespace string) error {
+	_, err := c.doRequest(ctx, http.MethodDelete, c.rulerURL(namespace), nil)
+	return err
+}
+
+// CreateAlertmanagerConfig replaces the Alertmanager configuration and templates of the tenant.
+func (c *mimirClient) CreateAlertmanagerConfig(ctx context.Context, cfg string, templates map[string]string) error {
+	payload, err := yaml.Marshal(&alertmanagerConfig{
+		TemplateFiles:      templates,
+		AlertmanagerConfig: cfg,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(ctx, http.MethodPost, c.endpoint.JoinPath(alertmanagerConfigAPIPath), payload)
+	return err
+}
+
+// GetAlertmanagerConfig returns the Alertmanager configuration and templates of the tenant.
+func (c *mimirClient) GetAlertmanagerConfig(ctx context.Context) (string, map[string]string, error) {
+	body, err := c.doRequest(ctx, http.MethodGet, c.endpoint.JoinPath(alertmanagerConfigAPIPath), nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var cfg alertmanagerConfig
+	if err := yaml.Unmarshal(body, &cfg); err != nil {
+		return "", nil, fmt.Errorf("unable to unmarshal Alertmanager configuration: %w", err)
+	}
+	return cfg.AlertmanagerConfig, cfg.TemplateFiles, nil
+}
+
+// DeleteAlermanagerConfig deletes the Alertmanager configuration of the tenant.
+func (c *mimirClient) DeleteAlermanagerConfig(ctx context.Context) error {
+	_, err := c.doRequest(ctx, http.MethodDelete, c.endpoint.JoinPath(alertmanagerConfigAPIPath), nil)
+	return err
+}
+
+// CreateSilence creates the silence, or replaces it when its ID is set, and
+// returns the ID of the silence. Alertmanager may give a new ID to a replaced silence.
+func (c *mimirClient) CreateSilence(ctx context.Context, silence alertmanagerSilence) (string, error) {
+	var resp struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := c.doJSONRequest(ctx, http.MethodPost, c.alertmanagerURL("silences"), &silence, &resp); err != nil {
+		return "", err
+	}
+	return resp.SilenceID, nil
+}
+
+// GetSilence returns the silence, including the expired ones.
+func (c *mimirClient) GetSilence(ctx context.Context, id string) (*alertmanagerSilence, error) {
+	var silence alertmanagerSilence
+	if err := c.doJSONRequest(ctx, http.MethodGet, c.alertmanagerURL("silence", id), nil, &silence); err != nil {
+		return nil, err
+	}
+	return &silence, nil
+}
+
+// ExpireSilence expires the silence, Alertmanager does not delete silences.
+func (c *mimirClient) ExpireSilence(ctx context.Context, id string) error {
+	return c.doJSONRequest(ctx, http.MethodDelete, c.alertmanagerURL("silence", id), nil, nil)
+}
+
+// ListRulerAlerts returns the pending and firing alerts of the ruler.
+func (c *mimirClient) ListRulerAlerts(ctx context.Context) ([]rulerAlert, error) {
+	var data struct {
+		Alerts []rulerAlert `json:"alerts"`
+	}
+	if err := c.doPrometheusRequest(ctx, c.prometheusURL("alerts"), &data); err != nil {
+		return nil, err
+	}
+	return data.Alerts, nil
+}
+
+// ListAlertmanagerAlerts returns the alerts received by Alertmanager which are
+// not resolved, including the silenced and inhibited ones.
+func (c *mimirClient) ListAlertmanagerAlerts(ctx context.Context) ([]alertmanagerAlert, error) {
+	var alerts []alertmanagerAlert
+	if err := c.doJSONRequest(ctx, http.MethodGet, c.alertmanagerURL("alerts"), nil, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// ListRuleGroupStatuses returns the evaluation status of the rule groups of the
+// namespace, as reported by the ruler.
+func (c *mimirClient) ListRuleGroupStatuses(ctx context.Context, namespace string) ([]ruleGroupStatus, error) {
+	u := c.prometheusURL("rules")
+	u.RawQuery = url.Values{"file[]": {namespace}}.Encode()
+	var data struct {
+		Groups []ruleGroupStatus `json:"groups"`
+	}
+	if err := c.doPrometheusRequest(ctx, u, &data); err != nil {
+		return nil, err
+	}
+	// Older Mimir versions ignore the filter
+	groups := make([]ruleGroupStatus, 0, len(data.Groups))
+	for _, group := range data.Groups {
+		if group.File == namespace {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/prometheus/prometheus/model/rulefmt"
+)
+
+func TestMimirClientPrometheusHTTPPrefix(t *testing.T) {
+	ctx := context.Background()
+	server := newTestMimirServer(t, "/custom/prometheus", "/alertmanager")
+
+	cli, err := newMimirClient(MimirClientConfig{
+		Address:              server.URL,
+		PrometheusHTTPPrefix: "/custom/prometheus",
+	}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	group := rwrulefmt.RuleGroup{RuleGroup: rulefmt.RuleGroup{Name: "group/1"}}
+	if err := cli.CreateRuleGroup(ctx, "team/a", group); err != nil {
+		t.Fatal(err)
+	}
+	ruleSet, err := cli.ListRules(ctx, "team/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ruleSet["team/a"]) != 1 || ruleSet["team/a"][0].Name != "group/1" {
+		t.Fatalf("unexpected rule groups: %#v", ruleSet)
+	}
+	if err := cli.DeleteRuleGroup(ctx, "team/a", "group/1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.ListRules(ctx, "team/a"); !errors.Is(err, client.ErrResourceNotFound) {
+		t.Fatalf("expected ErrResourceNotFound, got %v", err)
+	}
+
+	if err := cli.CreateAlertmanagerConfig(ctx, "route: {}", map[string]string{"default": "tmpl"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, templates, err := cli.GetAlertmanagerConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg != "route: {}" || templates["default"] != "tmpl" {
+		t.Fatalf("unexpected Alertmanager configuration: %q %v", cfg, templates)
+	}
+
+	// The default prefix is not served by this server
+	defaultCli, err := newMimirClient(MimirClientConfig{
+		Address:              server.URL,
+		PrometheusHTTPPrefix: "/prometheus",
+	}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := defaultCli.CreateRuleGroup(ctx, "team/a", group); !errors.Is(err, client.ErrResourceNotFound) {
+		t.Fatalf("expected ErrResourceNotFound, got %v", err)
+	}
+}
+
+func TestMimirClientAlertmanagerHTTPPrefix(t *testing.T) {
+	ctx := context.Background()
+	server := newTestMimirServer(t, "/prometheus", "/custom/alertmanager")
+
+	cli, err := newMimirClient(MimirClientConfig{
+		Address:                server.URL,
+		AlertmanagerHTTPPrefix: "/custom/alertmanager",
+	}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	silence := alertmanagerSilence{
+		Matchers:  []alertmanagerSilenceMatcher{{Name: "alertname", Value: "Watchdog", IsEqual: true}},
+		StartsAt:  time.Now(),
+		EndsAt:    time.Now().Add(time.Hour),
+		CreatedBy: "terraform",
+		Comment:   "test",
+	}
+	id, err := cli.CreateSilence(ctx, silence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote, err := cli.GetSilence(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remote.ID != id || remote.Comment != "test" || remote.Status == nil || remote.Status.State != "active" {
+		t.Fatalf("unexpected silence: %#v", remote)
+	}
+	if err := cli.ExpireSilence(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if remote, err = cli.GetSilence(ctx, id); err != nil || remote.Status.State != "expired" {
+		t.Fatalf("expected an expired silence, got %#v, %v", remote, err)
+	}
+	if _, err := cli.GetSilence(ctx, "unknown"); !errors.Is(err, client.ErrResourceNotFound) {
+		t.Fatalf("expected ErrResourceNotFound, got %v", err)
+	}
+
+	// The default prefix is not served by this server
+	defaultCli, err := newMimirClient(MimirClientConfig{
+		Address:                server.URL,
+		AlertmanagerHTTPPrefix: "/alertmanager",
+	}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := defaultCli.GetSilence(ctx, id); !errors.Is(err, client.ErrResourceNotFound) {
+		t.Fatalf("expected ErrResourceNotFound, got %v", err)
+	}
+}
+
+func TestMimirClientTenantHeader(t *testing.T) {
+	ctx := context.Background()
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	cli, err := newMimirClient(MimirClientConfig{Address: server.URL}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.DeleteNamespace(ctx, "demo"); err != nil {
+		t.Fatal(err)
+	}
+	if len(header.Values("X-Scope-OrgID")) != 0 {
+		t.Fatalf("expected no X-Scope-OrgID header without tenant, got %q", header.Get("X-Scope-OrgID"))
+	}
+
+	if err := cli.WithTenantID("tenant-a").DeleteNamespace(ctx, "demo"); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get("X-Scope-OrgID"); got != "tenant-a" {
+		t.Fatalf("expected X-Scope-OrgID tenant-a, got %q", got)
+	}
+}
+
+func TestMimirClientTenantBasicAuth(t *testing.T) {
+	ctx := context.Background()
+	var user, key, tenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, key, _ = r.BasicAuth()
+		tenant = r.Header.Get("X-Scope-OrgID")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	cli, err := newMimirClient(MimirClientConfig{
+		Address:  server.URL,
+		TenantID: "instance-1",
+		APIKey:   "secret",
+	}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.DeleteNamespace(ctx, "demo"); err != nil {
+		t.Fatal(err)
+	}
+	if user != "instance-1" || key != "secret" || tenant != "instance-1" {
+		t.Fatalf("unexpected credentials %q:%q for tenant %q", user, key, tenant)
+	}
+
+	// Overriding the tenant keeps the provider basic auth user
+	if err := cli.WithTenantID("tenant-a").DeleteNamespace(ctx, "demo"); err != nil {
+		t.Fatal(err)
+	}
+	if user != "instance-1" || key != "secret" || tenant != "tenant-a" {
+		t.Fatalf("unexpected credentials %q:%q for tenant %q", user, key, tenant)
+	}
+}
@@ -1,13 +1,47 @@
 package provider
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+// tenantClient returns the client to use for a resource: it targets the
+// resource's tenant_id when set, the provider's tenant otherwise.
+func tenantClient(cli mimirClientInterface, tenantID types.String) mimirClientInterface {
+	return cli.WithTenantID(tenantID.ValueString())
+}
+
+// tenantImportIDPrefix prefixes the import IDs of the resources of another
+// tenant than the provider one: tenant_id=<tenant_id>:<id>.
+const tenantImportIDPrefix = "tenant_id="
+
+// splitTenantImportID splits an import ID of the form [tenant_id=<tenant_id>:]<id>.
+// Tenant IDs cannot contain colons, so <id> is returned as is and may contain
+// slashes or colons. The tenant is returned as a null value when the ID is not
+// tenant qualified or the tenant is empty.
+func splitTenantImportID(importID string) (types.String, string) {
+	rest, ok := strings.CutPrefix(importID, tenantImportIDPrefix)
+	if !ok {
+		return types.StringNull(), importID
+	}
+	tenantID, id, _ := strings.Cut(rest, ":")
+	if tenantID == "" {
+		return types.StringNull(), id
+	}
+	return types.StringValue(tenantID), id
+}
+
+// isFullyKnown reports whether the value, including any nested value, is known.
+func isFullyKnown(ctx context.Context, value attr.Value) bool {
+	tfValue, err := value.ToTerraformValue(ctx)
+	return err == nil && tfValue.IsFullyKnown()
+}
+
 func hash(s string) string {
 	sha := sha256.Sum256([]byte(s))
 	return hex.EncodeToString(sha[:])
@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSplitTenantImportID(t *testing.T) {
+	for _, tc := range []struct {
+		importID string
+		tenantID types.String
+		id       string
+	}{
+		{importID: "demo", tenantID: types.StringNull(), id: "demo"},
+		{importID: "team/a", tenantID: types.StringNull(), id: "team/a"},
+		{importID: "team/a/group", tenantID: types.StringNull(), id: "team/a/group"},
+		{importID: "k8s:prod", tenantID: types.StringNull(), id: "k8s:prod"},
+		{importID: "alertmanager", tenantID: types.StringNull(), id: "alertmanager"},
+		{importID: "tenant_id=tenant-a:demo", tenantID: types.StringValue("tenant-a"), id: "demo"},
+		{importID: "tenant_id=tenant-a:team/a:b", tenantID: types.StringValue("tenant-a"), id: "team/a:b"},
+		{importID: "tenant_id=alertmanager", tenantID: types.StringValue("alertmanager"), id: ""},
+		{importID: "tenant_id=:demo", tenantID: types.StringNull(), id: "demo"},
+	} {
+		tenantID, id := splitTenantImportID(tc.importID)
+		if !tenantID.Equal(tc.tenantID) || id != tc.id {
+			t.Errorf("splitTenantImportID(%q) = %s, %q, expected %s, %q", tc.importID, tenantID, id, tc.tenantID, tc.id)
+		}
+	}
+}
@@ -5,13 +5,10 @@ package provider
 
 import (
 	"context"
-	"fmt"
 	"os"
 
-	"github.com/grafana/dskit/crypto/tls"
-	mimirtool "github.com/grafana/mimir/pkg/mimirtool/client"
-	mimirVersion "github.com/grafana/mimir/pkg/util/version"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -47,17 +44,25 @@ type MimirClientConfig struct {
 
 // MimirtoolProviderModel describes the provider data model.
 type MimirtoolProviderModel struct {
-	Address                types.String `tfsdk:"address"`
-	TenantID               types.String `tfsdk:"tenant_id"`
-	APIUser                types.String `tfsdk:"api_user"`
-	APIKey                 types.String `tfsdk:"api_key"`
-	AuthToken              types.String `tfsdk:"auth_token"`
-	TLSKeyPath             types.String `tfsdk:"tls_key_path"`
-	TLSCertPath            types.String `tfsdk:"tls_cert_path"`
-	TLSCAPath              types.String `tfsdk:"tls_ca_path"`
-	InsecureSkipVerify     types.Bool   `tfsdk:"insecure_skip_verify"`
-	PrometheusHTTPPrefix   types.String `tfsdk:"prometheus_http_prefix"`
-	AlertmanagerHTTPPrefix types.String `tfsdk:"alertmanager_http_prefix"`
+	Address                types.String     `tfsdk:"address"`
+	TenantID               types.String     `tfsdk:"tenant_id"`
+	APIUser                types.String     `tfsdk:"api_user"`
+	APIKey                 types.String     `tfsdk:"api_key"`
+	AuthToken              types.String     `tfsdk:"auth_token"`
+	TLSKeyPath             types.String     `tfsdk:"tls_key_path"`
+	TLSCertPath            types.String     `tfsdk:"tls_cert_path"`
+	TLSCAPath              types.String     `tfsdk:"tls_ca_path"`
+	InsecureSkipVerify     types.Bool       `tfsdk:"insecure_skip_verify"`
+	PrometheusHTTPPrefix   types.String     `tfsdk:"prometheus_http_prefix"`
+	AlertmanagerHTTPPrefix types.String     `tfsdk:"alertmanager_http_prefix"`
+	RulePolicy             *rulePolicyModel `tfsdk:"rule_policy"`
+}
+
+// providerData is handed to the resources and data sources: the client, along
+// with the provider settings which are not about contacting Grafana Mimir.
+type providerData struct {
+	mimirClientInterface
+	rulePolicy *rulePolicyModel
 }
 
 func (p *MimirtoolProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -108,14 +113,17 @@ func (p *MimirtoolProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 				Optional:            true,
 			},
 			"prometheus_http_prefix": schema.StringAttribute{
-				MarkdownDescription: "Path prefix to use for rules. May alternatively be set via the `MIMIRTOOL_PROMETHEUS_HTTP_PREFIX` or `MIMIR_PROMETHEUS_HTTP_PREFIX` environment variable.",
+				MarkdownDescription: "Path prefix under which Grafana Mimir serves the Prometheus API, the ruler configuration API is reached at `<prefix>/config/v1/rules`. Defaults to `/prometheus`. May alternatively be set via the `MIMIRTOOL_PROMETHEUS_HTTP_PREFIX` or `MIMIR_PROMETHEUS_HTTP_PREFIX` environment variable.",
 				Optional:            true,
 			},
 			"alertmanager_http_prefix": schema.StringAttribute{
-				MarkdownDescription: "Path prefix to use for alertmanager. May alternatively be set via the `MIMIRTOOL_ALERTMANAGER_HTTP_PREFIX` or `MIMIR_ALERTMANAGER_HTTP_PREFIX` environment variable.",
+				MarkdownDescription: "Path prefix under which Grafana Mimir serves the Alertmanager API, silences and alerts are reached at `<prefix>/api/v2`. It does not apply to the Alertmanager configuration API used by `mimirtool_alertmanager`, which Grafana Mimir always serves at `/api/v1/alerts`. Defaults to `/alertmanager`. May alternatively be set via the `MIMIRTOOL_ALERTMANAGER_HTTP_PREFIX` or `MIMIR_ALERTMANAGER_HTTP_PREFIX` environment variable.",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"rule_policy": rulePolicyProviderBlock(),
+		},
 	}
 }
 
@@ -161,6 +169,15 @@ func (p *MimirtoolProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
+	// Invalid policies are reported before any resource uses them
+	if data.RulePolicy != nil && data.RulePolicy.isKnown(ctx) {
+		_, diags := data.RulePolicy.policy(ctx, path.Root("rule_policy"))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Create a new Mimirtool client using the configuration values
 	var err error
 	c := &myClient{}
@@ -175,36 +192,34 @@ func (p *MimirtoolProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
-	resp.DataSourceData = c.cli
-	resp.ResourceData = c.cli
+	pd := &providerData{mimirClientInterface: c.cli, rulePolicy: data.RulePolicy}
+	resp.DataSourceData = pd
+	resp.ResourceData = pd
 }
 
 func getDefaultMimirClient(cfg MimirClientConfig, version string) (mimirClientInterface, error) {
-	mimirVersion.Version = fmt.Sprintf("terraform-provider-mimirtool-%s", version)
-	return mimirtool.New(mimirtool.Config{
-		AuthToken: cfg.AuthToken,
-		User:      cfg.APIUser,
-		Key:       cfg.APIKey,
-		Address:   cfg.Address,
-		ID:        cfg.TenantID,
-		TLS: tls.ClientConfig{
-			CAPath:             cfg.TLSCAPath,
-			CertPath:           cfg.TLSCertPath,
-			KeyPath:            cfg.TLSKeyPath,
-			InsecureSkipVerify: cfg.InsecureSkipVerify,
-		},
-	})
+	return newMimirClient(cfg, version)
 }
 
 func (p *MimirtoolProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewRulerNamespaceResource,
 		NewAlertmanagerResource,
+		NewRulerRuleGroupResource,
+		NewAlertmanagerFragmentResource,
+		NewAlertmanagerSilenceResource,
 	}
 }
 
 func (p *MimirtoolProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewRulerNamespaceDataSource,
+		NewRulerNamespacesDataSource,
+		NewAlertmanagerRouteTestDataSource,
+		NewAlertmanagerTemplatePreviewDataSource,
+		NewAlertsDataSource,
+		NewRulesStatusDataSource,
+	}
 }
 
 func New(version string) func() provider.Provider {
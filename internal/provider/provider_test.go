@@ -4,8 +4,33 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	amlabels "github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
 )
 
 // testAccProtoV6ProviderFactories is used to instantiate a provider during acceptance testing.
@@ -14,3 +39,594 @@ import (
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
 	"mimirtool": providerserver.NewProtocol6WithError(New("test")()),
 }
+
+var _ mimirClientInterface = &fakeMimirClient{}
+
+// fakeMimirClient is an in-memory implementation of mimirClientInterface used
+// by the unit tests which do not need a running Grafana Mimir.
+type fakeMimirClient struct {
+	namespaces            map[string][]rwrulefmt.RuleGroup
+	alertmanagerConfig    string
+	alertmanagerTemplates map[string]string
+	silences              map[string]alertmanagerSilence
+	alerts                []alertmanagerAlert
+	// failGroup makes CreateRuleGroup fail for the group with this name
+	failGroup string
+}
+
+func newFakeMimirClient() *fakeMimirClient {
+	return &fakeMimirClient{namespaces: map[string][]rwrulefmt.RuleGroup{}, silences: map[string]alertmanagerSilence{}}
+}
+
+func (c *fakeMimirClient) DeleteRuleGroup(_ context.Context, namespace string, groupName string) error {
+	groups, ok := c.namespaces[namespace]
+	if !ok {
+		return client.ErrResourceNotFound
+	}
+	for i, group := range groups {
+		if group.Name == groupName {
+			groups = append(groups[:i:i], groups[i+1:]...)
+			if len(groups) == 0 {
+				delete(c.namespaces, namespace)
+			} else {
+				c.namespaces[namespace] = groups
+			}
+			return nil
+		}
+	}
+	return client.ErrResourceNotFound
+}
+
+func (c *fakeMimirClient) GetRuleGroup(_ context.Context, namespace string, groupName string) (*rwrulefmt.RuleGroup, error) {
+	for _, group := range c.namespaces[namespace] {
+		if group.Name == groupName {
+			return &group, nil
+		}
+	}
+	return nil, client.ErrResourceNotFound
+}
+
+func (c *fakeMimirClient) ListRules(_ context.Context, namespace string) (map[string][]rwrulefmt.RuleGroup, error) {
+	if namespace == "" {
+		return c.namespaces, nil
+	}
+	groups, ok := c.namespaces[namespace]
+	if !ok {
+		return nil, client.ErrResourceNotFound
+	}
+	return map[string][]rwrulefmt.RuleGroup{namespace: groups}, nil
+}
+
+func (c *fakeMimirClient) DeleteNamespace(_ context.Context, namespace string) error {
+	if _, ok := c.namespaces[namespace]; !ok {
+		return client.ErrResourceNotFound
+	}
+	delete(c.namespaces, namespace)
+	return nil
+}
+
+func (c *fakeMimirClient) CreateRuleGroup(_ context.Context, namespace string, rg rwrulefmt.RuleGroup) error {
+	if rg.Name == c.failGroup {
+		return fmt.Errorf("server returned HTTP status: 400 Bad Request")
+	}
+	groups := c.namespaces[namespace]
+	for i, group := range groups {
+		if group.Name == rg.Name {
+			groups[i] = rg
+			return nil
+		}
+	}
+	c.namespaces[namespace] = append(groups, rg)
+	return nil
+}
+
+func (c *fakeMimirClient) CreateAlertmanagerConfig(_ context.Context, cfg string, templates map[string]string) error {
+	c.alertmanagerConfig = cfg
+	c.alertmanagerTemplates = templates
+	return nil
+}
+
+func (c *fakeMimirClient) GetAlertmanagerConfig(_ context.Context) (string, map[string]string, error) {
+	if c.alertmanagerConfig == "" {
+		return "", nil, client.ErrResourceNotFound
+	}
+	return c.alertmanagerConfig, c.alertmanagerTemplates, nil
+}
+
+func (c *fakeMimirClient) DeleteAlermanagerConfig(_ context.Context) error {
+	c.alertmanagerConfig = ""
+	c.alertmanagerTemplates = nil
+	return nil
+}
+
+// CreateSilence behaves as Alertmanager: silences starting in the past start
+// now, and a silence which has already expired is replaced by a new one.
+func (c *fakeMimirClient) CreateSilence(_ context.Context, silence alertmanagerSilence) (string, error) {
+	now := time.Now()
+	if silence.ID != "" {
+		existing, ok := c.silences[silence.ID]
+		if !ok {
+			return "", fmt.Errorf("silence %s not found", silence.ID)
+		}
+		if !existing.EndsAt.After(now) {
+			silence.ID = ""
+		}
+	}
+	if !silence.EndsAt.After(now) {
+		return "", fmt.Errorf("silence invalid: end time can't be in the past")
+	}
+	if silence.ID == "" {
+		silence.ID = fmt.Sprintf("silence-%d", len(c.silences)+1)
+		if silence.StartsAt.Before(now) {
+			silence.StartsAt = now
+		}
+	}
+	silence.Status = nil
+	c.silences[silence.ID] = silence
+	return silence.ID, nil
+}
+
+func (c *fakeMimirClient) GetSilence(_ context.Context, id string) (*alertmanagerSilence, error) {
+	silence, ok := c.silences[id]
+	if !ok {
+		return nil, client.ErrResourceNotFound
+	}
+	now := time.Now()
+	state := "active"
+	switch {
+	case !silence.EndsAt.After(now):
+		state = "expired"
+	case silence.StartsAt.After(now):
+		state = "pending"
+	}
+	silence.Status = &alertmanagerSilenceStatus{State: state}
+	return &silence, nil
+}
+
+func (c *fakeMimirClient) ExpireSilence(_ context.Context, id string) error {
+	silence, ok := c.silences[id]
+	if !ok {
+		return client.ErrResourceNotFound
+	}
+	now := time.Now()
+	if !silence.EndsAt.After(now) {
+		return fmt.Errorf("silence %s already expired", id)
+	}
+	silence.EndsAt = now
+	c.silences[id] = silence
+	return nil
+}
+
+// fakeRulerAlertsActiveAt is the time the alerts of the fake ruler became active.
+var fakeRulerAlertsActiveAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// ListRulerAlerts reports every alerting rule as an alert, pending when the
+// rule has a for duration and firing otherwise.
+func (c *fakeMimirClient) ListRulerAlerts(_ context.Context) ([]rulerAlert, error) {
+	namespaces := make([]string, 0, len(c.namespaces))
+	for namespace := range c.namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	alerts := []rulerAlert{}
+	for _, namespace := range namespaces {
+		for _, group := range c.namespaces[namespace] {
+			for _, rule := range group.Rules {
+				if rule.Alert.Value == "" {
+					continue
+				}
+				labels := map[string]string{"alertname": rule.Alert.Value}
+				for name, value := range rule.Labels {
+					labels[name] = value
+				}
+				state := "firing"
+				if rule.For != 0 {
+					state = "pending"
+				}
+				alerts = append(alerts, rulerAlert{
+					Labels:      labels,
+					Annotations: rule.Annotations,
+					State:       state,
+					ActiveAt:    &fakeRulerAlertsActiveAt,
+					Value:       "1e+00",
+				})
+			}
+		}
+	}
+	return alerts, nil
+}
+
+// ListRuleGroupStatuses reports the rules as just evaluated, as if the fake ruler
+// evaluated them continuously. Rules using group_left fail, as with a
+// many-to-many matching.
+func (c *fakeMimirClient) ListRuleGroupStatuses(_ context.Context, namespace string) ([]ruleGroupStatus, error) {
+	now := time.Now()
+	groups := []ruleGroupStatus{}
+	for _, group := range c.namespaces[namespace] {
+		status := ruleGroupStatus{
+			Name:           group.Name,
+			File:           namespace,
+			Rules:          []ruleStatus{},
+			Interval:       time.Duration(group.Interval).Seconds(),
+			LastEvaluation: now,
+			EvaluationTime: 0.002,
+		}
+		for _, rule := range group.Rules {
+			rs := ruleStatus{
+				Name:           rule.Record.Value,
+				Query:          rule.Expr.Value,
+				Type:           "recording",
+				Health:         "ok",
+				LastEvaluation: now,
+				EvaluationTime: 0.001,
+			}
+			if rule.Alert.Value != "" {
+				rs.Name, rs.Type, rs.State = rule.Alert.Value, "alerting", "firing"
+			}
+			if strings.Contains(rule.Expr.Value, "group_left") {
+				rs.Health = "err"
+				rs.LastError = "found duplicate series for the match group {instance=\"a\"} on the right hand-side of the operation: many-to-many matching not allowed: matching labels must be unique on one side"
+			}
+			status.Rules = append(status.Rules, rs)
+		}
+		groups = append(groups, status)
+	}
+	return groups, nil
+}
+
+// PostAlerts receives alerts as Alertmanager does, alerts without start time
+// start now.
+func (c *fakeMimirClient) PostAlerts(alerts []alertmanagerAlert) {
+	for _, alert := range alerts {
+		if alert.StartsAt.IsZero() {
+			alert.StartsAt = time.Now()
+		}
+		alert.Fingerprint = fmt.Sprintf("%016x", model.LabelsToSignature(alert.Labels))
+		c.alerts = append(c.alerts, alert)
+	}
+}
+
+// ListAlertmanagerAlerts returns the alerts which are not resolved, the alerts
+// matching an active silence are suppressed.
+func (c *fakeMimirClient) ListAlertmanagerAlerts(ctx context.Context) ([]alertmanagerAlert, error) {
+	now := time.Now()
+	alerts := []alertmanagerAlert{}
+	for _, alert := range c.alerts {
+		if !alert.EndsAt.IsZero() && !alert.EndsAt.After(now) {
+			continue
+		}
+		alert.Status = alertmanagerAlertStatus{State: "active", SilencedBy: []string{}, InhibitedBy: []string{}}
+		for id := range c.silences {
+			silence, _ := c.GetSilence(ctx, id)
+			if silence.Status.State == "active" && fakeSilenceMatches(*silence, alert.Labels) {
+				alert.Status.State = "suppressed"
+				alert.Status.SilencedBy = append(alert.Status.SilencedBy, id)
+			}
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+func fakeSilenceMatches(silence alertmanagerSilence, labels map[string]string) bool {
+	for _, m := range silence.Matchers {
+		matchType := amlabels.MatchEqual
+		switch {
+		case m.IsRegex && m.IsEqual:
+			matchType = amlabels.MatchRegexp
+		case m.IsRegex:
+			matchType = amlabels.MatchNotRegexp
+		case !m.IsEqual:
+			matchType = amlabels.MatchNotEqual
+		}
+		matcher, err := amlabels.NewMatcher(matchType, m.Name, m.Value)
+		if err != nil || !matcher.Matches(labels[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *fakeMimirClient) WithTenantID(_ string) mimirClientInterface {
+	return c
+}
+
+// newTestMimirServer starts an HTTP server emulating the Grafana Mimir ruler
+// and Alertmanager APIs, with the ruler API served under prometheusHTTPPrefix
+// and the Alertmanager silences API under alertmanagerHTTPPrefix. Each tenant
+// gets its own in-memory backend.
+func newTestMimirServer(t *testing.T, prometheusHTTPPrefix, alertmanagerHTTPPrefix string) *httptest.Server {
+	t.Helper()
+	tenants := map[string]*fakeMimirClient{}
+	var mu sync.Mutex
+	backend := func(r *http.Request) *fakeMimirClient {
+		tenantID := r.Header.Get("X-Scope-OrgID")
+		if _, ok := tenants[tenantID]; !ok {
+			tenants[tenantID] = newFakeMimirClient()
+		}
+		return tenants[tenantID]
+	}
+	writeError := func(w http.ResponseWriter, err error) {
+		if errors.Is(err, client.ErrResourceNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+	writeYAML := func(w http.ResponseWriter, v interface{}) {
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(out)
+	}
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		out, err := json.Marshal(v)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(out)
+	}
+
+	rulerPath := path.Join("/", prometheusHTTPPrefix, rulerConfigAPIPath)
+	mux := http.NewServeMux()
+	rulerHandler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		cli := backend(r)
+		var elem []string
+		for _, e := range strings.Split(strings.Trim(strings.TrimPrefix(r.URL.EscapedPath(), rulerPath), "/"), "/") {
+			if e == "" {
+				continue
+			}
+			unescaped, err := url.PathUnescape(e)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			elem = append(elem, unescaped)
+		}
+
+		switch {
+		case len(elem) == 0 && r.Method == http.MethodGet:
+			ruleSet, err := cli.ListRules(r.Context(), "")
+			if err != nil || len(ruleSet) == 0 {
+				http.Error(w, "no rule groups found", http.StatusNotFound)
+				return
+			}
+			writeYAML(w, ruleSet)
+		case len(elem) == 1 && r.Method == http.MethodGet:
+			ruleSet, err := cli.ListRules(r.Context(), elem[0])
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeYAML(w, ruleSet)
+		case len(elem) == 1 && r.Method == http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			var rg rwrulefmt.RuleGroup
+			if err := yaml.Unmarshal(body, &rg); err != nil {
+				writeError(w, err)
+				return
+			}
+			if err := cli.CreateRuleGroup(r.Context(), elem[0], rg); err != nil {
+				writeError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		case len(elem) == 1 && r.Method == http.MethodDelete:
+			if err := cli.DeleteNamespace(r.Context(), elem[0]); err != nil {
+				writeError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		case len(elem) == 2 && r.Method == http.MethodGet:
+			rg, err := cli.GetRuleGroup(r.Context(), elem[0], elem[1])
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeYAML(w, rg)
+		case len(elem) == 2 && r.Method == http.MethodDelete:
+			if err := cli.DeleteRuleGroup(r.Context(), elem[0], elem[1]); err != nil {
+				writeError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.NotFound(w, r)
+		}
+	}
+	mux.HandleFunc(rulerPath, rulerHandler)
+	mux.HandleFunc(rulerPath+"/", rulerHandler)
+	mux.HandleFunc(alertmanagerConfigAPIPath, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		cli := backend(r)
+
+		switch r.Method {
+		case http.MethodGet:
+			cfg, templates, err := cli.GetAlertmanagerConfig(r.Context())
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeYAML(w, alertmanagerConfig{AlertmanagerConfig: cfg, TemplateFiles: templates})
+		case http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			var cfg alertmanagerConfig
+			if err := yaml.Unmarshal(body, &cfg); err != nil {
+				writeError(w, err)
+				return
+			}
+			if err := cli.CreateAlertmanagerConfig(r.Context(), cfg.AlertmanagerConfig, cfg.TemplateFiles); err != nil {
+				writeError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			_ = cli.DeleteAlermanagerConfig(r.Context())
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	mux.HandleFunc("GET "+path.Join("/", prometheusHTTPPrefix, prometheusAPIPath, "alerts"), func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		alerts, err := backend(r).ListRulerAlerts(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"status": "success", "data": map[string]interface{}{"alerts": alerts}})
+	})
+
+	mux.HandleFunc("GET "+path.Join("/", prometheusHTTPPrefix, prometheusAPIPath, "rules"), func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		groups := []ruleGroupStatus{}
+		for _, namespace := range r.URL.Query()["file[]"] {
+			statuses, err := backend(r).ListRuleGroupStatuses(r.Context(), namespace)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			groups = append(groups, statuses...)
+		}
+		writeJSON(w, map[string]interface{}{"status": "success", "data": map[string]interface{}{"groups": groups}})
+	})
+
+	alertmanagerAPI := path.Join("/", alertmanagerHTTPPrefix, alertmanagerAPIPath)
+	mux.HandleFunc("GET "+alertmanagerAPI+"/alerts", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		alerts, err := backend(r).ListAlertmanagerAlerts(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, alerts)
+	})
+	mux.HandleFunc("POST "+alertmanagerAPI+"/alerts", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var alerts []alertmanagerAlert
+		if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+			writeError(w, err)
+			return
+		}
+		backend(r).PostAlerts(alerts)
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("POST "+alertmanagerAPI+"/silences", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var silence alertmanagerSilence
+		if err := json.NewDecoder(r.Body).Decode(&silence); err != nil {
+			writeError(w, err)
+			return
+		}
+		id, err := backend(r).CreateSilence(r.Context(), silence)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, map[string]string{"silenceID": id})
+	})
+	mux.HandleFunc("GET "+alertmanagerAPI+"/silence/{id}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		silence, err := backend(r).GetSilence(r.Context(), r.PathValue("id"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, silence)
+	})
+	mux.HandleFunc("DELETE "+alertmanagerAPI+"/silence/{id}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := backend(r).ExpireSilence(r.Context(), r.PathValue("id")); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAccProviderHTTPPrefix(t *testing.T) {
+	server := newTestMimirServer(t, "/custom/prometheus", "/custom/alertmanager")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccProviderHTTPPrefix, server.URL),
+				ConfigStateChecks: []statecheck.StateCheck{
+					SemanticYAMLStateCheck("mimirtool_ruler_namespace.demo", "remote_config_yaml", testAccResourceNamespaceYaml),
+					statecheck.ExpectKnownValue("mimirtool_alertmanager_silence.demo", tfjsonpath.New("status"), knownvalue.StringExact("active")),
+				},
+			},
+		},
+	})
+}
+
+const testAccProviderHTTPPrefix = `
+provider "mimirtool" {
+  address                  = %q
+  prometheus_http_prefix   = "/custom/prometheus"
+  alertmanager_http_prefix = "/custom/alertmanager"
+}
+
+resource "mimirtool_ruler_namespace" "demo" {
+	namespace = "demo"
+	config_yaml = file("testdata/rules.yaml")
+}
+
+resource "mimirtool_alertmanager" "demo" {
+	config_yaml = file("testdata/example_alertmanager_config.yaml")
+	templates_config_yaml = {
+	  default_template = file("testdata/example_alertmanager_template.tmpl")
+	}
+}
+
+resource "mimirtool_alertmanager_silence" "demo" {
+  matchers   = [{ name = "cluster", value = "eu-west-1" }]
+  duration   = "1h"
+  comment    = "Maintenance"
+  created_by = "terraform"
+}
+`
+
+// testAccMimirClient returns a client to the Grafana Mimir instance used by the
+// acceptance tests, to make changes outside of Terraform.
+func testAccMimirClient(t *testing.T) mimirClientInterface {
+	t.Helper()
+	address := os.Getenv("MIMIRTOOL_ADDRESS")
+	if address == "" {
+		address = "http://localhost:8080"
+	}
+	cli, err := newMimirClient(MimirClientConfig{Address: address, PrometheusHTTPPrefix: "/prometheus", AlertmanagerHTTPPrefix: "/alertmanager"}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cli
+}
@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// checkRecordingRules checks that the names of the recording rules follow the
+// level:metric:operations convention, see https://prometheus.io/docs/practices/rules/.
+// A name without colon, or without the three parts in strict mode, is an error.
+// A level missing the aggregation labels of the expression, or operations not
+// naming any operation of the expression, are warnings, errors in strict mode.
+// Each rule is reported in its own diagnostic.
+func checkRecordingRules(groups []rwrulefmt.RuleGroup, strict bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, group := range groups {
+		for i, rule := range group.Rules {
+			if rule.Record.Value == "" {
+				continue
+			}
+			shapeErr, reasons := recordingRuleNameIssues(rule, strict)
+			prefix := fmt.Sprintf("group %q: rule %d (%s)", group.Name, i, ruleDisplayName(rule))
+			switch {
+			case shapeErr != "":
+				diags.AddError(
+					"Invalid recording rule name",
+					fmt.Sprintf("%s: %s. Expected the level:metric:operations shape, e.g. \"job:http_requests:rate5m\".", prefix, shapeErr),
+				)
+			case len(reasons) > 0 && strict:
+				diags.AddError(
+					"Invalid recording rule name",
+					fmt.Sprintf("%s: %s.", prefix, strings.Join(reasons, ", ")),
+				)
+			case len(reasons) > 0:
+				diags.AddWarning(
+					"Recording rule name does not match best practices",
+					fmt.Sprintf("%s: %s.", prefix, strings.Join(reasons, ", ")),
+				)
+			}
+		}
+	}
+	return diags
+}
+
+// recordingRuleNameIssues returns why the name of the recording rule does not
+// have the level:metric:operations shape, and otherwise how its level and
+// operations do not describe its expression.
+func recordingRuleNameIssues(rule rulefmt.RuleNode, strict bool) (string, []string) {
+	parts := strings.Split(rule.Record.Value, ":")
+	switch {
+	case len(parts) < 2:
+		return "the name contains no colon", nil
+	case strict && len(parts) < 3:
+		return fmt.Sprintf("the name has %d parts instead of 3", len(parts)), nil
+	}
+
+	expr, err := parser.ParseExpr(rule.Expr.Value)
+	if err != nil {
+		// Invalid expressions are reported by the validation
+		return "", nil
+	}
+
+	var reasons []string
+	level := "_" + parts[0] + "_"
+	var missing []string
+	for _, label := range aggregationLabels(expr) {
+		if !strings.Contains(level, "_"+label+"_") {
+			missing = append(missing, fmt.Sprintf("%q", label))
+		}
+	}
+	if len(missing) > 0 {
+		reasons = append(reasons, fmt.Sprintf("the level %q is missing the aggregation labels %s of the expression", parts[0], strings.Join(missing, ", ")))
+	}
+
+	if len(parts) >= 3 {
+		operations := parts[len(parts)-1]
+		names := operationNames(expr)
+		if len(names) > 0 && !slices.ContainsFunc(names, func(name string) bool { return strings.Contains(operations, name) }) {
+			reasons = append(reasons, fmt.Sprintf("the operations %q do not name any operation of the expression, such as %s", operations, strings.Join(names, ", ")))
+		}
+	}
+	return "", reasons
+}
+
+// aggregationLabels returns the labels kept by the outermost aggregation of the
+// expression, looking through parentheses and function calls. The le label
+// aggregated for histogram_quantile is not kept in its output.
+func aggregationLabels(expr parser.Expr) []string {
+	quantile := false
+	for {
+		switch e := expr.(type) {
+		case *parser.ParenExpr:
+			expr = e.Expr
+		case *parser.Call:
+			quantile = quantile || e.Func.Name == "histogram_quantile"
+			var arg parser.Expr
+			for _, a := range e.Args {
+				if a.Type() == parser.ValueTypeVector {
+					arg = a
+				}
+			}
+			if arg == nil {
+				return nil
+			}
+			expr = arg
+		case *parser.AggregateExpr:
+			if e.Without {
+				return nil
+			}
+			labels := make([]string, 0, len(e.Grouping))
+			for _, label := range e.Grouping {
+				if !(quantile && label == "le") {
+					labels = append(labels, label)
+				}
+			}
+			return labels
+		default:
+			return nil
+		}
+	}
+}
+
+// operationNames returns the names of the aggregations and functions of the
+// expression, as they are usually written in the operations of a recording
+// rule name, e.g. rate for rate() and quantile for histogram_quantile().
+func operationNames(expr parser.Expr) []string {
+	var names []string
+	add := func(name string) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		var name string
+		switch n := node.(type) {
+		case *parser.AggregateExpr:
+			name = n.Op.String()
+		case *parser.Call:
+			name = strings.TrimSuffix(n.Func.Name, "_over_time")
+			if name == "histogram_quantile" {
+				name = "quantile"
+			}
+		default:
+			return nil
+		}
+		add(name)
+		// Averages are commonly named means
+		if name == "avg" {
+			add("mean")
+		}
+		return nil
+	})
+	return names
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheckRecordingRules(t *testing.T) {
+	group := func(records ...[2]string) []rwrulefmt.RuleGroup {
+		g := rwrulefmt.RuleGroup{RuleGroup: rulefmt.RuleGroup{Name: "demo"}}
+		for _, r := range records {
+			g.Rules = append(g.Rules, rulefmt.RuleNode{Record: yaml.Node{Value: r[0]}, Expr: yaml.Node{Value: r[1]}})
+		}
+		return []rwrulefmt.RuleGroup{g}
+	}
+	groups := group(
+		[2]string{"cluster_job:cortex_request_duration_seconds:99quantile", "histogram_quantile(0.99, sum by (le, cluster, job) (rate(cortex_request_duration_seconds_bucket[1m])))"},
+		[2]string{"job:http_requests:rate5m", "sum by (job, status_code) (rate(http_requests_total[5m]))"},
+		[2]string{"job_status_code:http_requests:sum", "sum by (job, status_code) (rate(http_requests_total[5m]))"},
+		[2]string{"job:http_requests:total", "sum by (job) (rate(http_requests_total[5m]))"},
+		[2]string{"instance:latency_seconds:mean5m", "avg_over_time(latency_seconds[5m])"},
+		[2]string{"job:up", "sum by (job) (up)"},
+		[2]string{"http_requests_total_rate", "rate(http_requests_total[5m])"},
+	)
+
+	diags := checkRecordingRules(groups, false)
+	var errors, warnings []string
+	for _, d := range diags.Errors() {
+		errors = append(errors, d.Detail())
+	}
+	for _, d := range diags.Warnings() {
+		warnings = append(warnings, d.Detail())
+	}
+	expectedErrors := []string{
+		`group "demo": rule 6 (record "http_requests_total_rate"): the name contains no colon. Expected the level:metric:operations shape, e.g. "job:http_requests:rate5m".`,
+	}
+	expectedWarnings := []string{
+		`group "demo": rule 1 (record "job:http_requests:rate5m"): the level "job" is missing the aggregation labels "status_code" of the expression.`,
+		`group "demo": rule 3 (record "job:http_requests:total"): the operations "total" do not name any operation of the expression, such as sum, rate.`,
+	}
+	if !reflect.DeepEqual(errors, expectedErrors) {
+		t.Errorf("unexpected errors\nExpected: %q\nActual: %q", expectedErrors, errors)
+	}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("unexpected warnings\nExpected: %q\nActual: %q", expectedWarnings, warnings)
+	}
+
+	// Strict checks turn the warnings into errors and require the three parts
+	diags = checkRecordingRules(groups, true)
+	if diags.ErrorsCount() != 4 || diags.WarningsCount() != 0 {
+		t.Fatalf("expected 4 errors in strict mode, got %v", diags)
+	}
+	if detail := diags.Errors()[2].Detail(); detail != `group "demo": rule 5 (record "job:up"): the name has 2 parts instead of 3. Expected the level:metric:operations shape, e.g. "job:http_requests:rate5m".` {
+		t.Errorf("unexpected strict error %q", detail)
+	}
+}
@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	defaultHealthyEvaluationTimeout  = "5m"
+	defaultHealthyEvaluationSeverity = "error"
+)
+
+// ruleEvaluationPollInterval is how often the ruler status API is polled while
+// waiting for the rule groups to be evaluated.
+var ruleEvaluationPollInterval = 5 * time.Second
+
+// waitForHealthyEvaluationModel describes the `wait_for_healthy_evaluation` block.
+type waitForHealthyEvaluationModel struct {
+	Timeout  types.String `tfsdk:"timeout"`
+	Severity types.String `tfsdk:"severity"`
+}
+
+// waitForHealthyEvaluationBlock returns the schema of the `wait_for_healthy_evaluation` block.
+func waitForHealthyEvaluationBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Waits after the rule groups are written until every group of the namespace has been evaluated by the ruler, " +
+			"then reports the rules whose evaluation failed, e.g. because of a many-to-many matching.",
+		Attributes: map[string]schema.Attribute{
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait for every group to be evaluated, as a Prometheus duration. Defaults to `" + defaultHealthyEvaluationTimeout + "`, or to twice the largest group `interval` when longer.",
+				Optional:            true,
+			},
+			"severity": schema.StringAttribute{
+				MarkdownDescription: "How failed evaluations, and groups not evaluated before the timeout, are reported: `error` fails the apply, `warning` only warns. Defaults to `" + defaultHealthyEvaluationSeverity + "`. " +
+					"They are always reported as warnings when the namespace is created, as an error would taint it and the next apply would delete the namespace before creating it again.",
+				Optional: true,
+			},
+		},
+	}
+}
+
+// validate reports an invalid timeout or severity.
+func (m *waitForHealthyEvaluationModel) validate(diagnostics *diag.Diagnostics) {
+	if m == nil {
+		return
+	}
+	blockPath := path.Root("wait_for_healthy_evaluation")
+	if !m.Timeout.IsNull() && !m.Timeout.IsUnknown() {
+		if d, err := model.ParseDuration(m.Timeout.ValueString()); err != nil || d <= 0 {
+			diagnostics.AddAttributeError(blockPath.AtName("timeout"), "Invalid duration", fmt.Sprintf("%q is not a positive Prometheus duration.", m.Timeout.ValueString()))
+		}
+	}
+	if !m.Severity.IsNull() && !m.Severity.IsUnknown() {
+		if severity := m.Severity.ValueString(); severity != "error" && severity != "warning" {
+			diagnostics.AddAttributeError(blockPath.AtName("severity"), "Invalid severity", fmt.Sprintf("The severity must be \"error\" or \"warning\", got %q.", severity))
+		}
+	}
+}
+
+// timeout returns the configured timeout. The default one leaves time for the
+// groups with the largest interval to be evaluated.
+func (m *waitForHealthyEvaluationModel) timeout(groups []rwrulefmt.RuleGroup) time.Duration {
+	timeout := defaultHealthyEvaluationTimeout
+	if !m.Timeout.IsNull() {
+		timeout = m.Timeout.ValueString()
+	}
+	// The timeout is validated at plan time
+	d, _ := model.ParseDuration(timeout)
+	if m.Timeout.IsNull() {
+		for _, group := range groups {
+			d = max(d, 2*group.Interval)
+		}
+	}
+	return time.Duration(d)
+}
+
+// report adds the diagnostic with the configured severity.
+func (m *waitForHealthyEvaluationModel) report(diagnostics *diag.Diagnostics, summary, detail string) {
+	if m.Severity.ValueString() == "warning" {
+		diagnostics.AddWarning(summary, detail)
+		return
+	}
+	diagnostics.AddError(summary, detail)
+}
+
+// lastRuleGroupEvaluations returns when each group of the namespace was last
+// evaluated, so that evaluations which happen after the groups are written can
+// be told apart, regardless of the clock of the ruler.
+func lastRuleGroupEvaluations(ctx context.Context, cli mimirClientInterface, namespace string) (map[string]time.Time, error) {
+	statuses, err := cli.ListRuleGroupStatuses(ctx, namespace)
+	if err != nil && !errors.Is(err, client.ErrResourceNotFound) {
+		return nil, err
+	}
+	evaluations := make(map[string]time.Time, len(statuses))
+	for _, status := range statuses {
+		evaluations[status.Name] = status.LastEvaluation
+	}
+	return evaluations, nil
+}
+
+// waitForHealthyEvaluation polls the ruler until every group has been evaluated
+// after its prior evaluation, then reports the rules whose last evaluation
+// failed. Groups not evaluated before the timeout are reported as well.
+func waitForHealthyEvaluation(ctx context.Context, cli mimirClientInterface, namespace string, ruleGroups []rwrulefmt.RuleGroup, prior map[string]time.Time, wait *waitForHealthyEvaluationModel, diagnostics *diag.Diagnostics) {
+	timeout := wait.timeout(ruleGroups)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	groups := ruleGroupNames(ruleGroups)
+
+	var (
+		pending  []string
+		failures []string
+		lastErr  error
+	)
+	for {
+		statuses, err := cli.ListRuleGroupStatuses(ctx, namespace)
+		if err != nil {
+			// The namespace may not be loaded by the ruler yet
+			lastErr = err
+			pending = groups
+		} else {
+			lastErr = nil
+			pending, failures = ruleGroupsEvaluationHealth(statuses, groups, prior)
+		}
+		tflog.Debug(ctx, "Waiting for rule groups evaluation", map[string]interface{}{
+			"namespace": namespace,
+			"pending":   pending,
+			"error":     lastErr,
+		})
+		if len(pending) == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			detail := fmt.Sprintf("The rule groups of namespace %q were not evaluated by the ruler within %s: %s.", namespace, timeout, strings.Join(quoteAll(pending), ", "))
+			if lastErr != nil {
+				detail += fmt.Sprintf("\n\nLast error reading the rules status: %s", lastErr.Error())
+			}
+			wait.report(diagnostics, "Rule groups not evaluated", detail)
+			return
+		case <-time.After(ruleEvaluationPollInterval):
+		}
+	}
+
+	if len(failures) > 0 {
+		wait.report(
+			diagnostics,
+			"Rule evaluation failed",
+			fmt.Sprintf("The following rules of namespace %q failed their evaluation:\n  - %s", namespace, strings.Join(failures, "\n  - ")),
+		)
+	}
+}
+
+// ruleGroupsEvaluationHealth returns the groups which were not evaluated since
+// their prior evaluation, and a description of every rule of the evaluated
+// groups whose evaluation failed.
+func ruleGroupsEvaluationHealth(statuses []ruleGroupStatus, groups []string, prior map[string]time.Time) ([]string, []string) {
+	byName := make(map[string]ruleGroupStatus, len(statuses))
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+
+	var pending, failures []string
+	for _, name := range groups {
+		status, ok := byName[name]
+		if !ok || status.LastEvaluation.IsZero() || !status.LastEvaluation.After(prior[name]) {
+			pending = append(pending, name)
+			continue
+		}
+		for i, rule := range status.Rules {
+			if rule.Health == "err" {
+				failures = append(failures, fmt.Sprintf("group %q: rule %d (%s): %s", name, i, ruleStatusDisplayName(rule), rule.LastError))
+			}
+		}
+	}
+	return pending, failures
+}
+
+// ruleStatusDisplayName returns the type and name of a rule, as ruleDisplayName.
+func ruleStatusDisplayName(rule ruleStatus) string {
+	if rule.Type == "recording" {
+		return fmt.Sprintf("record %q", rule.Name)
+	}
+	return fmt.Sprintf("alert %q", rule.Name)
+}
+
+func ruleGroupNames(groups []rwrulefmt.RuleGroup) []string {
+	names := make([]string, 0, len(groups))
+	for _, group := range groups {
+		names = append(names, group.Name)
+	}
+	return names
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", v))
+	}
+	return quoted
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWaitForHealthyEvaluation(t *testing.T) {
+	ctx := context.Background()
+	defer func(interval time.Duration) { ruleEvaluationPollInterval = interval }(ruleEvaluationPollInterval)
+	ruleEvaluationPollInterval = 10 * time.Millisecond
+
+	cli := newFakeMimirClient()
+	cli.namespaces["demo"] = []rwrulefmt.RuleGroup{
+		{RuleGroup: rulefmt.RuleGroup{Name: "healthy", Rules: []rulefmt.RuleNode{
+			{Record: yaml.Node{Value: "job:up:sum"}, Expr: yaml.Node{Value: "sum by (job) (up)"}},
+		}}},
+		{RuleGroup: rulefmt.RuleGroup{Name: "joins", Rules: []rulefmt.RuleNode{
+			{Alert: yaml.Node{Value: "VersionDown"}, Expr: yaml.Node{Value: "up * on (instance) group_left (version) build_info == 0"}},
+		}}},
+	}
+	prior := map[string]time.Time{"healthy": time.Now()}
+	wait := &waitForHealthyEvaluationModel{Timeout: types.StringValue("1s"), Severity: types.StringNull()}
+
+	var diags diag.Diagnostics
+	waitForHealthyEvaluation(ctx, cli, "demo", cli.namespaces["demo"][:1], prior, wait, &diags)
+	if diags.HasError() || diags.WarningsCount() != 0 {
+		t.Fatalf("expected no diagnostics, got %v", diags)
+	}
+
+	diags = nil
+	waitForHealthyEvaluation(ctx, cli, "demo", cli.namespaces["demo"], prior, wait, &diags)
+	if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), `group "joins": rule 0 (alert "VersionDown"): found duplicate series`) {
+		t.Fatalf("expected the failed evaluation of VersionDown to be reported, got %v", diags)
+	}
+
+	// Groups never evaluated are reported once the timeout expires
+	diags = nil
+	wait = &waitForHealthyEvaluationModel{Timeout: types.StringValue("50ms"), Severity: types.StringValue("warning")}
+	groups := []rwrulefmt.RuleGroup{cli.namespaces["demo"][0], {RuleGroup: rulefmt.RuleGroup{Name: "missing"}}}
+	waitForHealthyEvaluation(ctx, cli, "demo", groups, prior, wait, &diags)
+	if diags.HasError() || diags.WarningsCount() != 1 || !strings.Contains(diags.Warnings()[0].Detail(), `"missing"`) {
+		t.Fatalf("expected a warning about the missing group, got %v", diags)
+	}
+}
+
+func TestWaitForHealthyEvaluationTimeout(t *testing.T) {
+	groups := []rwrulefmt.RuleGroup{
+		{RuleGroup: rulefmt.RuleGroup{Name: "default"}},
+		{RuleGroup: rulefmt.RuleGroup{Name: "hourly", Interval: model.Duration(time.Hour)}},
+	}
+	wait := &waitForHealthyEvaluationModel{Timeout: types.StringNull()}
+	if timeout := wait.timeout(groups[:1]); timeout != 5*time.Minute {
+		t.Errorf("expected the default timeout, got %s", timeout)
+	}
+	// The default timeout leaves time for the hourly group to be evaluated
+	if timeout := wait.timeout(groups); timeout != 2*time.Hour {
+		t.Errorf("expected a timeout of twice the largest interval, got %s", timeout)
+	}
+	wait.Timeout = types.StringValue("10m")
+	if timeout := wait.timeout(groups); timeout != 10*time.Minute {
+		t.Errorf("expected the configured timeout, got %s", timeout)
+	}
+}
+
+func TestRuleGroupsEvaluationHealth(t *testing.T) {
+	written := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	statuses := []ruleGroupStatus{
+		{Name: "reloaded", LastEvaluation: written, Rules: []ruleStatus{{Name: "a", Type: "recording", Health: "err", LastError: "boom"}}},
+		{Name: "evaluated", LastEvaluation: written.Add(time.Minute), Rules: []ruleStatus{{Name: "b", Type: "recording", Health: "err", LastError: "boom"}}},
+		{Name: "new", Rules: []ruleStatus{{Name: "c", Type: "alerting", Health: "unknown"}}},
+	}
+	prior := map[string]time.Time{"reloaded": written, "evaluated": written}
+
+	pending, failures := ruleGroupsEvaluationHealth(statuses, []string{"reloaded", "evaluated", "new", "missing"}, prior)
+	if strings.Join(pending, ",") != "reloaded,new,missing" {
+		t.Fatalf("unexpected pending groups %v", pending)
+	}
+	if len(failures) != 1 || failures[0] != `group "evaluated": rule 0 (record "b"): boom` {
+		t.Fatalf("unexpected failures %q", failures)
+	}
+}
@@ -0,0 +1,328 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/mimir/pkg/mimirtool/rules"
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
+)
+
+// ruleGroupModel describes a `group` block, the HCL counterpart of a rule group
+// of a namespace YAML definition.
+type ruleGroupModel struct {
+	Name          types.String `tfsdk:"name"`
+	Interval      types.String `tfsdk:"interval"`
+	QueryOffset   types.String `tfsdk:"query_offset"`
+	Limit         types.Int64  `tfsdk:"limit"`
+	SourceTenants types.List   `tfsdk:"source_tenants"`
+	Rules         []ruleModel  `tfsdk:"rule"`
+}
+
+// ruleModel describes a `rule` block, either an alerting or a recording rule.
+type ruleModel struct {
+	Alert         types.String `tfsdk:"alert"`
+	Record        types.String `tfsdk:"record"`
+	Expr          types.String `tfsdk:"expr"`
+	For           types.String `tfsdk:"for"`
+	KeepFiringFor types.String `tfsdk:"keep_firing_for"`
+	Labels        types.Map    `tfsdk:"labels"`
+	Annotations   types.Map    `tfsdk:"annotations"`
+}
+
+var ruleAttrTypes = map[string]attr.Type{
+	"alert":           types.StringType,
+	"record":          types.StringType,
+	"expr":            types.StringType,
+	"for":             types.StringType,
+	"keep_firing_for": types.StringType,
+	"labels":          types.MapType{ElemType: types.StringType},
+	"annotations":     types.MapType{ElemType: types.StringType},
+}
+
+var ruleGroupAttrTypes = map[string]attr.Type{
+	"name":           types.StringType,
+	"interval":       types.StringType,
+	"query_offset":   types.StringType,
+	"limit":          types.Int64Type,
+	"source_tenants": types.ListType{ElemType: types.StringType},
+	"rule":           types.ListType{ElemType: types.ObjectType{AttrTypes: ruleAttrTypes}},
+}
+
+// ruleGroupListType is the type of the `group` block list.
+var ruleGroupListType = types.ListType{ElemType: types.ObjectType{AttrTypes: ruleGroupAttrTypes}}
+
+// ruleListType is the type of the `rule` block list.
+var ruleListType = types.ListType{ElemType: types.ObjectType{AttrTypes: ruleAttrTypes}}
+
+// ruleBlock returns the schema of the `rule` block.
+func ruleBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		MarkdownDescription: "An alerting or recording rule of the group, evaluated in the order of the blocks.",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"alert": schema.StringAttribute{
+					MarkdownDescription: "The name of the alert. Conflicts with `record`.",
+					Optional:            true,
+				},
+				"record": schema.StringAttribute{
+					MarkdownDescription: "The name of the time series to output to. Conflicts with `alert`.",
+					Optional:            true,
+				},
+				"expr": schema.StringAttribute{
+					MarkdownDescription: "The PromQL expression to evaluate.",
+					Required:            true,
+				},
+				"for": schema.StringAttribute{
+					MarkdownDescription: "How long the alert condition must hold before the alert fires, e.g. `5m`. Alerting rules only.",
+					Optional:            true,
+				},
+				"keep_firing_for": schema.StringAttribute{
+					MarkdownDescription: "How long the alert keeps firing after its condition cleared, e.g. `5m`. Alerting rules only.",
+					Optional:            true,
+				},
+				"labels": schema.MapAttribute{
+					MarkdownDescription: "Labels to add or overwrite.",
+					ElementType:         types.StringType,
+					Optional:            true,
+				},
+				"annotations": schema.MapAttribute{
+					MarkdownDescription: "Annotations to add to the alert. Alerting rules only.",
+					ElementType:         types.StringType,
+					Optional:            true,
+				},
+			},
+		},
+	}
+}
+
+// ruleGroupAttributes returns the attributes of a rule group, shared by the
+// `group` block and the mimirtool_ruler_rule_group resource.
+func ruleGroupAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			MarkdownDescription: "The name of the rule group, unique within the namespace.",
+			Required:            true,
+		},
+		"interval": schema.StringAttribute{
+			MarkdownDescription: "How often the rules of the group are evaluated, e.g. `1m`. Defaults to the ruler evaluation interval.",
+			Optional:            true,
+		},
+		"query_offset": schema.StringAttribute{
+			MarkdownDescription: "The duration by which to delay the evaluation of the rules, e.g. `1m`.",
+			Optional:            true,
+		},
+		"limit": schema.Int64Attribute{
+			MarkdownDescription: "Limit the number of alerts an alerting rule and series a recording rule can produce. 0 is no limit.",
+			Optional:            true,
+		},
+		"source_tenants": schema.ListAttribute{
+			MarkdownDescription: "Tenants to query data from for federated rule groups.",
+			ElementType:         types.StringType,
+			Optional:            true,
+		},
+	}
+}
+
+// ruleGroupBlock returns the schema of the `group` block.
+func ruleGroupBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		MarkdownDescription: "A rule group of the namespace. Conflicts with `config_yaml`.",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: ruleGroupAttributes(),
+			Blocks: map[string]schema.Block{
+				"rule": ruleBlock(),
+			},
+		},
+	}
+}
+
+// ruleGroupsFromList converts the `group` blocks to rule groups.
+func ruleGroupsFromList(ctx context.Context, groups types.List) ([]rwrulefmt.RuleGroup, diag.Diagnostics) {
+	var models []ruleGroupModel
+	diags := groups.ElementsAs(ctx, &models, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	ruleGroups := make([]rwrulefmt.RuleGroup, 0, len(models))
+	for _, m := range models {
+		group, err := ruleGroupFromModel(ctx, m)
+		if err != nil {
+			diags.AddError("Invalid rule group", err.Error())
+			return nil, diags
+		}
+		ruleGroups = append(ruleGroups, group)
+	}
+	return ruleGroups, diags
+}
+
+// ruleGroupFromModel converts a `group` block to a rule group.
+func ruleGroupFromModel(ctx context.Context, m ruleGroupModel) (rwrulefmt.RuleGroup, error) {
+	group := rwrulefmt.RuleGroup{RuleGroup: rulefmt.RuleGroup{
+		Name:  m.Name.ValueString(),
+		Limit: int(m.Limit.ValueInt64()),
+	}}
+
+	var err error
+	if group.Interval, err = parseOptionalDuration(m.Interval); err != nil {
+		return group, fmt.Errorf("group %q: invalid interval: %w", group.Name, err)
+	}
+	if !m.QueryOffset.IsNull() {
+		queryOffset, err := parseOptionalDuration(m.QueryOffset)
+		if err != nil {
+			return group, fmt.Errorf("group %q: invalid query_offset: %w", group.Name, err)
+		}
+		group.QueryOffset = &queryOffset
+	}
+	if !m.SourceTenants.IsNull() {
+		if diags := m.SourceTenants.ElementsAs(ctx, &group.SourceTenants, false); diags.HasError() {
+			return group, fmt.Errorf("group %q: invalid source_tenants", group.Name)
+		}
+	}
+
+	group.Rules = make([]rulefmt.RuleNode, 0, len(m.Rules))
+	for i, r := range m.Rules {
+		rule, err := ruleNodeFromModel(r)
+		if err != nil {
+			return group, fmt.Errorf("group %q: rule %d: %w", group.Name, i, err)
+		}
+		group.Rules = append(group.Rules, rule)
+	}
+	return group, nil
+}
+
+// ruleNodeFromModel converts a `rule` block to a rule.
+func ruleNodeFromModel(m ruleModel) (rulefmt.RuleNode, error) {
+	rule := rulefmt.RuleNode{
+		Record:      stringNode(m.Record.ValueString()),
+		Alert:       stringNode(m.Alert.ValueString()),
+		Expr:        stringNode(m.Expr.ValueString()),
+		Labels:      mapStringFromTypesMap(m.Labels),
+		Annotations: mapStringFromTypesMap(m.Annotations),
+	}
+
+	var err error
+	if rule.For, err = parseOptionalDuration(m.For); err != nil {
+		return rule, fmt.Errorf("invalid for: %w", err)
+	}
+	if rule.KeepFiringFor, err = parseOptionalDuration(m.KeepFiringFor); err != nil {
+		return rule, fmt.Errorf("invalid keep_firing_for: %w", err)
+	}
+	return rule, nil
+}
+
+// ruleGroupModelsFromRuleGroups converts rule groups to `group` blocks.
+func ruleGroupModelsFromRuleGroups(ctx context.Context, groups []rwrulefmt.RuleGroup) (types.List, diag.Diagnostics) {
+	models := make([]ruleGroupModel, 0, len(groups))
+	for _, group := range groups {
+		models = append(models, ruleGroupModelFromRuleGroup(group))
+	}
+	return types.ListValueFrom(ctx, ruleGroupListType.ElemType, models)
+}
+
+// ruleGroupModelFromRuleGroup converts a rule group to a `group` block, unset
+// fields are converted to null values.
+func ruleGroupModelFromRuleGroup(group rwrulefmt.RuleGroup) ruleGroupModel {
+	m := ruleGroupModel{
+		Name:          types.StringValue(group.Name),
+		Interval:      durationValue(group.Interval),
+		QueryOffset:   types.StringNull(),
+		Limit:         types.Int64Null(),
+		SourceTenants: types.ListNull(types.StringType),
+		Rules:         make([]ruleModel, 0, len(group.Rules)),
+	}
+	if group.QueryOffset != nil {
+		m.QueryOffset = types.StringValue(group.QueryOffset.String())
+	}
+	if group.Limit != 0 {
+		m.Limit = types.Int64Value(int64(group.Limit))
+	}
+	if len(group.SourceTenants) > 0 {
+		tenants := make([]attr.Value, 0, len(group.SourceTenants))
+		for _, tenant := range group.SourceTenants {
+			tenants = append(tenants, types.StringValue(tenant))
+		}
+		m.SourceTenants = types.ListValueMust(types.StringType, tenants)
+	}
+	for _, rule := range group.Rules {
+		m.Rules = append(m.Rules, ruleModelFromRuleNode(rule))
+	}
+	return m
+}
+
+// ruleModelFromRuleNode converts a rule to a `rule` block.
+func ruleModelFromRuleNode(rule rulefmt.RuleNode) ruleModel {
+	return ruleModel{
+		Alert:         optionalStringValue(rule.Alert.Value),
+		Record:        optionalStringValue(rule.Record.Value),
+		Expr:          types.StringValue(rule.Expr.Value),
+		For:           durationValue(rule.For),
+		KeepFiringFor: durationValue(rule.KeepFiringFor),
+		Labels:        typeMapFromMapString(emptyToNil(rule.Labels)),
+		Annotations:   typeMapFromMapString(emptyToNil(rule.Annotations)),
+	}
+}
+
+// ruleNamespaceFromGroups validates the rule groups the same way as a namespace
+// YAML definition, by parsing the definition holding them.
+func ruleNamespaceFromGroups(ctx context.Context, groups []rwrulefmt.RuleGroup) (rules.RuleNamespace, error) {
+	configYAML, err := ruleGroupsYAML(groups)
+	if err != nil {
+		return rules.RuleNamespace{}, err
+	}
+	return getRuleNamespaceFromYAML(ctx, configYAML)
+}
+
+// ruleGroupsYAML returns the namespace YAML definition holding the rule groups.
+func ruleGroupsYAML(groups []rwrulefmt.RuleGroup) (string, error) {
+	out, err := yaml.Marshal(map[string][]rwrulefmt.RuleGroup{"groups": groups})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal rule groups: %w", err)
+	}
+	return string(out), nil
+}
+
+func parseOptionalDuration(value types.String) (model.Duration, error) {
+	if value.IsNull() || value.ValueString() == "" {
+		return 0, nil
+	}
+	return model.ParseDuration(value.ValueString())
+}
+
+func durationValue(d model.Duration) types.String {
+	if d == 0 {
+		return types.StringNull()
+	}
+	return types.StringValue(d.String())
+}
+
+func optionalStringValue(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+func emptyToNil(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// stringNode returns a YAML node holding the string, or an empty node so that
+// it is omitted when marshalling.
+func stringNode(s string) yaml.Node {
+	if s == "" {
+		return yaml.Node{}
+	}
+	return yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
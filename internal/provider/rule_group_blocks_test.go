@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestRuleGroupBlocksRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	for _, file := range []string{"testdata/rules.yaml", "testdata/rules2.yaml", "testdata/rules-quoting.yaml"} {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		namespace, err := getRuleNamespaceFromYAML(ctx, string(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		blocks, diags := ruleGroupModelsFromRuleGroups(ctx, namespace.Groups)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		groups, diags := ruleGroupsFromList(ctx, blocks)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+
+		if len(groups) != len(namespace.Groups) {
+			t.Fatalf("%s: expected %d groups, got %d", file, len(namespace.Groups), len(groups))
+		}
+		for i := range groups {
+			if !ruleGroupsEqual(groups[i], namespace.Groups[i]) {
+				t.Errorf("%s: group %q changed after a round trip through group blocks", file, groups[i].Name)
+			}
+		}
+
+		// The YAML built from the blocks goes through the same parser as config_yaml
+		configYAML, err := ruleGroupsYAML(groups)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ruleNamespaceYAMLEqual(configYAML, string(content)) {
+			t.Errorf("%s: expected YAML built from group blocks to be equivalent, got:\n%s", file, configYAML)
+		}
+	}
+}
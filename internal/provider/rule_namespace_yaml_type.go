@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure the custom type satisfies the framework interfaces.
+var (
+	_ basetypes.StringTypable                    = RuleNamespaceYAMLType{}
+	_ basetypes.StringValuableWithSemanticEquals = RuleNamespaceYAMLValue{}
+)
+
+// RuleNamespaceYAMLType is a string type holding a rule namespace YAML definition.
+// Its values are compared semantically: indentation, quoting, key order and the
+// formatting of PromQL expressions are not meaningful.
+type RuleNamespaceYAMLType struct {
+	basetypes.StringType
+}
+
+func (t RuleNamespaceYAMLType) Equal(o attr.Type) bool {
+	other, ok := o.(RuleNamespaceYAMLType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t RuleNamespaceYAMLType) String() string {
+	return "RuleNamespaceYAMLType"
+}
+
+func (t RuleNamespaceYAMLType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return RuleNamespaceYAMLValue{StringValue: in}, nil
+}
+
+func (t RuleNamespaceYAMLType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+	return stringValuable, nil
+}
+
+func (t RuleNamespaceYAMLType) ValueType(_ context.Context) attr.Value {
+	return RuleNamespaceYAMLValue{}
+}
+
+// RuleNamespaceYAMLValue is a value of RuleNamespaceYAMLType.
+type RuleNamespaceYAMLValue struct {
+	basetypes.StringValue
+}
+
+// NewRuleNamespaceYAMLValue returns a known RuleNamespaceYAMLValue.
+func NewRuleNamespaceYAMLValue(value string) RuleNamespaceYAMLValue {
+	return RuleNamespaceYAMLValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func (v RuleNamespaceYAMLValue) Equal(o attr.Value) bool {
+	other, ok := o.(RuleNamespaceYAMLValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v RuleNamespaceYAMLValue) Type(_ context.Context) attr.Type {
+	return RuleNamespaceYAMLType{}
+}
+
+// StringSemanticEquals reports whether both values define the same rule groups.
+// Values which cannot be parsed are compared as plain strings.
+func (v RuleNamespaceYAMLValue) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(RuleNamespaceYAMLValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			"An unexpected value type was received while performing semantic equality checks. "+
+				"Please report this to the provider developers.\n\n"+
+				"Expected Value Type: "+fmt.Sprintf("%T", v)+"\n"+
+				"Got Value Type: "+fmt.Sprintf("%T", newValuable),
+		)
+		return false, diags
+	}
+
+	return ruleNamespaceYAMLEqual(v.ValueString(), newValue.ValueString()), diags
+}
+
+// ruleNamespaceYAMLEqual reports whether two namespace definitions hold the same rule groups.
+func ruleNamespaceYAMLEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	namespaceA, err := parseNormalizedNamespace(a)
+	if err != nil {
+		return false
+	}
+	namespaceB, err := parseNormalizedNamespace(b)
+	if err != nil {
+		return false
+	}
+	return len(describeRuleGroupsDrift(namespaceA.Groups, namespaceB.Groups)) == 0
+}
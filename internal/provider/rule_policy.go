@@ -0,0 +1,258 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// rulePolicyModel describes the `rule_policy` block of the provider and of the
+// mimirtool_ruler_namespace and mimirtool_ruler_rule_group resources.
+type rulePolicyModel struct {
+	RequiredLabels      types.List   `tfsdk:"required_labels"`
+	AllowedLabelValues  types.Map    `tfsdk:"allowed_label_values"`
+	RequiredAnnotations types.List   `tfsdk:"required_annotations"`
+	AlertNamePattern    types.String `tfsdk:"alert_name_pattern"`
+	RecordNamePattern   types.String `tfsdk:"record_name_pattern"`
+	ForbiddenFunctions  types.List   `tfsdk:"forbidden_functions"`
+}
+
+const rulePolicyDescription = "Naming and labeling policy the rules must follow. It is checked at plan time, or before the rules are written to Grafana Mimir when they are only known at apply time."
+
+var rulePolicyAttributeDescriptions = map[string]string{
+	"required_labels":      "Labels every alerting rule must set.",
+	"allowed_label_values": "Allowed values of labels, by label name. Rules setting one of these labels must use one of its values.",
+	"required_annotations": "Annotations every alerting rule must set, e.g. `runbook_url`.",
+	"alert_name_pattern":   "Regular expression the names of the alerting rules must fully match.",
+	"record_name_pattern":  "Regular expression the names of the recording rules must fully match.",
+	"forbidden_functions":  "PromQL functions the expressions of the rules must not use, e.g. `label_replace`.",
+}
+
+// rulePolicyProviderBlock returns the schema of the provider `rule_policy` block.
+func rulePolicyProviderBlock() providerschema.SingleNestedBlock {
+	d := rulePolicyAttributeDescriptions
+	return providerschema.SingleNestedBlock{
+		MarkdownDescription: rulePolicyDescription + " Applies to `mimirtool_ruler_namespace` and `mimirtool_ruler_rule_group`, and can be overridden attribute by attribute by their `rule_policy` block.",
+		Attributes: map[string]providerschema.Attribute{
+			"required_labels":      providerschema.ListAttribute{MarkdownDescription: d["required_labels"], ElementType: types.StringType, Optional: true},
+			"allowed_label_values": providerschema.MapAttribute{MarkdownDescription: d["allowed_label_values"], ElementType: types.ListType{ElemType: types.StringType}, Optional: true},
+			"required_annotations": providerschema.ListAttribute{MarkdownDescription: d["required_annotations"], ElementType: types.StringType, Optional: true},
+			"alert_name_pattern":   providerschema.StringAttribute{MarkdownDescription: d["alert_name_pattern"], Optional: true},
+			"record_name_pattern":  providerschema.StringAttribute{MarkdownDescription: d["record_name_pattern"], Optional: true},
+			"forbidden_functions":  providerschema.ListAttribute{MarkdownDescription: d["forbidden_functions"], ElementType: types.StringType, Optional: true},
+		},
+	}
+}
+
+// rulePolicyResourceBlock returns the schema of the resource `rule_policy` block.
+func rulePolicyResourceBlock() schema.SingleNestedBlock {
+	d := rulePolicyAttributeDescriptions
+	return schema.SingleNestedBlock{
+		MarkdownDescription: rulePolicyDescription + " Each attribute set overrides the one of the provider `rule_policy` block.",
+		Attributes: map[string]schema.Attribute{
+			"required_labels":      schema.ListAttribute{MarkdownDescription: d["required_labels"], ElementType: types.StringType, Optional: true},
+			"allowed_label_values": schema.MapAttribute{MarkdownDescription: d["allowed_label_values"], ElementType: types.ListType{ElemType: types.StringType}, Optional: true},
+			"required_annotations": schema.ListAttribute{MarkdownDescription: d["required_annotations"], ElementType: types.StringType, Optional: true},
+			"alert_name_pattern":   schema.StringAttribute{MarkdownDescription: d["alert_name_pattern"], Optional: true},
+			"record_name_pattern":  schema.StringAttribute{MarkdownDescription: d["record_name_pattern"], Optional: true},
+			"forbidden_functions":  schema.ListAttribute{MarkdownDescription: d["forbidden_functions"], ElementType: types.StringType, Optional: true},
+		},
+	}
+}
+
+// withOverrides returns the policy where the attributes set in the overrides
+// replace the ones of the policy. Either policy may be nil.
+func (m *rulePolicyModel) withOverrides(overrides *rulePolicyModel) *rulePolicyModel {
+	switch {
+	case m == nil:
+		return overrides
+	case overrides == nil:
+		return m
+	}
+	merged := *m
+	if !overrides.RequiredLabels.IsNull() {
+		merged.RequiredLabels = overrides.RequiredLabels
+	}
+	if !overrides.AllowedLabelValues.IsNull() {
+		merged.AllowedLabelValues = overrides.AllowedLabelValues
+	}
+	if !overrides.RequiredAnnotations.IsNull() {
+		merged.RequiredAnnotations = overrides.RequiredAnnotations
+	}
+	if !overrides.AlertNamePattern.IsNull() {
+		merged.AlertNamePattern = overrides.AlertNamePattern
+	}
+	if !overrides.RecordNamePattern.IsNull() {
+		merged.RecordNamePattern = overrides.RecordNamePattern
+	}
+	if !overrides.ForbiddenFunctions.IsNull() {
+		merged.ForbiddenFunctions = overrides.ForbiddenFunctions
+	}
+	return &merged
+}
+
+// isKnown reports whether every attribute of the policy is known.
+func (m *rulePolicyModel) isKnown(ctx context.Context) bool {
+	for _, value := range []attr.Value{m.RequiredLabels, m.AllowedLabelValues, m.RequiredAnnotations, m.AlertNamePattern, m.RecordNamePattern, m.ForbiddenFunctions} {
+		if !isFullyKnown(ctx, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// rulePolicy is the policy of a rulePolicyModel, ready to be enforced.
+type rulePolicy struct {
+	requiredLabels      []string
+	allowedLabelValues  map[string][]string
+	requiredAnnotations []string
+	alertNamePattern    string
+	alertNameRegexp     *regexp.Regexp
+	recordNamePattern   string
+	recordNameRegexp    *regexp.Regexp
+	forbiddenFunctions  []string
+}
+
+// policy converts the model, reporting invalid patterns and unknown functions
+// on the attributes of the block at the given path.
+func (m *rulePolicyModel) policy(ctx context.Context, blockPath path.Path) (*rulePolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	p := &rulePolicy{}
+	diags.Append(m.RequiredLabels.ElementsAs(ctx, &p.requiredLabels, true)...)
+	diags.Append(m.AllowedLabelValues.ElementsAs(ctx, &p.allowedLabelValues, true)...)
+	diags.Append(m.RequiredAnnotations.ElementsAs(ctx, &p.requiredAnnotations, true)...)
+	diags.Append(m.ForbiddenFunctions.ElementsAs(ctx, &p.forbiddenFunctions, true)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	for _, name := range p.forbiddenFunctions {
+		if _, ok := parser.Functions[name]; !ok {
+			diags.AddAttributeError(blockPath.AtName("forbidden_functions"), "Unknown PromQL function", fmt.Sprintf("%q is not a PromQL function.", name))
+		}
+	}
+	compile := func(value types.String, name string) *regexp.Regexp {
+		if value.IsNull() {
+			return nil
+		}
+		re, err := regexp.Compile("^(?:" + value.ValueString() + ")$")
+		if err != nil {
+			diags.AddAttributeError(blockPath.AtName(name), "Invalid regular expression", fmt.Sprintf("%q is not a valid regular expression: %s", value.ValueString(), err.Error()))
+		}
+		return re
+	}
+	p.alertNamePattern, p.alertNameRegexp = m.AlertNamePattern.ValueString(), compile(m.AlertNamePattern, "alert_name_pattern")
+	p.recordNamePattern, p.recordNameRegexp = m.RecordNamePattern.ValueString(), compile(m.RecordNamePattern, "record_name_pattern")
+	if diags.HasError() {
+		return nil, diags
+	}
+	return p, diags
+}
+
+// rulePolicyViolation describes a rule which does not follow the policy.
+type rulePolicyViolation struct {
+	// group and rule are the indexes of the rule
+	group, rule int
+	detail      string
+}
+
+// violations returns every violation of the policy by the rules of the groups.
+func (p *rulePolicy) violations(groups []rwrulefmt.RuleGroup) []rulePolicyViolation {
+	var violations []rulePolicyViolation
+	for i, group := range groups {
+		for j, rule := range group.Rules {
+			prefix := fmt.Sprintf("group %q: rule %d (%s)", group.Name, j, ruleDisplayName(rule))
+			if line := ruleLine(rule); line > 0 {
+				prefix += fmt.Sprintf(" at line %d", line)
+			}
+			for _, reason := range p.ruleViolations(rule) {
+				violations = append(violations, rulePolicyViolation{group: i, rule: j, detail: prefix + ": " + reason})
+			}
+		}
+	}
+	return violations
+}
+
+func (p *rulePolicy) ruleViolations(rule rulefmt.RuleNode) []string {
+	var reasons []string
+	if rule.Alert.Value != "" {
+		if p.alertNameRegexp != nil && !p.alertNameRegexp.MatchString(rule.Alert.Value) {
+			reasons = append(reasons, fmt.Sprintf("the name does not match the pattern %q", p.alertNamePattern))
+		}
+		for _, label := range p.requiredLabels {
+			if _, ok := rule.Labels[label]; !ok {
+				reasons = append(reasons, fmt.Sprintf("the required label %q is missing", label))
+			}
+		}
+		for _, annotation := range p.requiredAnnotations {
+			if _, ok := rule.Annotations[annotation]; !ok {
+				reasons = append(reasons, fmt.Sprintf("the required annotation %q is missing", annotation))
+			}
+		}
+	} else if p.recordNameRegexp != nil && !p.recordNameRegexp.MatchString(rule.Record.Value) {
+		reasons = append(reasons, fmt.Sprintf("the name does not match the pattern %q", p.recordNamePattern))
+	}
+
+	for _, label := range slices.Sorted(maps.Keys(p.allowedLabelValues)) {
+		value, ok := rule.Labels[label]
+		if ok && !slices.Contains(p.allowedLabelValues[label], value) {
+			reasons = append(reasons, fmt.Sprintf("the value %q of label %q is not one of %q", value, label, p.allowedLabelValues[label]))
+		}
+	}
+
+	if len(p.forbiddenFunctions) > 0 {
+		// Invalid expressions are reported by the validation
+		if expr, err := parser.ParseExpr(rule.Expr.Value); err == nil {
+			var used []string
+			parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+				if call, ok := node.(*parser.Call); ok && slices.Contains(p.forbiddenFunctions, call.Func.Name) && !slices.Contains(used, call.Func.Name) {
+					used = append(used, call.Func.Name)
+				}
+				return nil
+			})
+			for _, name := range used {
+				reasons = append(reasons, fmt.Sprintf("the expression uses the forbidden function %s()", name))
+			}
+		}
+	}
+	return reasons
+}
+
+// enforceRulePolicy reports every rule of the groups which does not follow the
+// provider policy overridden by the resource one, on the path returned by
+// violationPath. Nothing is reported while the policy is unknown.
+func enforceRulePolicy(ctx context.Context, providerPolicy, resourcePolicy *rulePolicyModel, groups []rwrulefmt.RuleGroup, violationPath func(rulePolicyViolation) path.Path, diagnostics *diag.Diagnostics) {
+	policyModel := providerPolicy.withOverrides(resourcePolicy)
+	if policyModel == nil || !policyModel.isKnown(ctx) {
+		return
+	}
+	// Invalid policies are reported by the provider and by ValidateConfig
+	policy, diags := policyModel.policy(ctx, path.Root("rule_policy"))
+	if diags.HasError() {
+		return
+	}
+	for _, violation := range policy.violations(groups) {
+		diagnostics.AddAttributeError(violationPath(violation), "Rule policy violation", violation.detail)
+	}
+}
+
+// ruleLine returns the line of the rule in the YAML definition it was read
+// from, 0 when unknown.
+func ruleLine(rule rulefmt.RuleNode) int {
+	if rule.Alert.Line > 0 {
+		return rule.Alert.Line
+	}
+	return rule.Record.Line
+}
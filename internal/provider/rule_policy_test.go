@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testRulePolicyModel() *rulePolicyModel {
+	return &rulePolicyModel{
+		RequiredLabels: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("severity"), types.StringValue("team")}),
+		AllowedLabelValues: types.MapValueMust(types.ListType{ElemType: types.StringType}, map[string]attr.Value{
+			"severity": types.ListValueMust(types.StringType, []attr.Value{types.StringValue("critical"), types.StringValue("warning")}),
+		}),
+		RequiredAnnotations: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("runbook_url")}),
+		AlertNamePattern:    types.StringValue("[A-Z][A-Za-z]+"),
+		RecordNamePattern:   types.StringValue("[a-z_]+:[a-z_]+:[a-z0-9_]+"),
+		ForbiddenFunctions:  types.ListValueMust(types.StringType, []attr.Value{types.StringValue("label_replace")}),
+	}
+}
+
+func TestRulePolicyViolations(t *testing.T) {
+	ctx := context.Background()
+	namespace, err := getRuleNamespaceFromYAML(ctx, `groups:
+  - name: alerts
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+        labels:
+          severity: critical
+          team: infra
+        annotations:
+          runbook_url: https://runbooks.example.com/instance-down
+      - alert: high_latency
+        expr: label_replace(latency_seconds, "svc", "$1", "job", "(.*)") > 1
+        labels:
+          severity: page
+  - name: records
+    rules:
+      - record: job:up:sum
+        expr: sum by (job) (up)
+      - record: job_up_count
+        expr: count by (job) (up)
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy, diags := testRulePolicyModel().policy(ctx, path.Root("rule_policy"))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var details []string
+	for _, violation := range policy.violations(namespace.Groups) {
+		details = append(details, violation.detail)
+	}
+	expected := []string{
+		`group "alerts": rule 1 (alert "high_latency") at line 11: the name does not match the pattern "[A-Z][A-Za-z]+"`,
+		`group "alerts": rule 1 (alert "high_latency") at line 11: the required label "team" is missing`,
+		`group "alerts": rule 1 (alert "high_latency") at line 11: the required annotation "runbook_url" is missing`,
+		`group "alerts": rule 1 (alert "high_latency") at line 11: the value "page" of label "severity" is not one of ["critical" "warning"]`,
+		`group "alerts": rule 1 (alert "high_latency") at line 11: the expression uses the forbidden function label_replace()`,
+		`group "records": rule 1 (record "job_up_count") at line 19: the name does not match the pattern "[a-z_]+:[a-z_]+:[a-z0-9_]+"`,
+	}
+	if !reflect.DeepEqual(details, expected) {
+		t.Fatalf("unexpected violations\nExpected: %q\nActual: %q", expected, details)
+	}
+}
+
+func TestRulePolicyOverrides(t *testing.T) {
+	ctx := context.Background()
+	provider := testRulePolicyModel()
+	overrides := &rulePolicyModel{
+		RequiredLabels:      types.ListValueMust(types.StringType, []attr.Value{types.StringValue("severity")}),
+		AllowedLabelValues:  types.MapNull(types.ListType{ElemType: types.StringType}),
+		RequiredAnnotations: types.ListNull(types.StringType),
+		AlertNamePattern:    types.StringNull(),
+		RecordNamePattern:   types.StringNull(),
+		ForbiddenFunctions:  types.ListValueMust(types.StringType, []attr.Value{}),
+	}
+
+	merged := provider.withOverrides(overrides)
+	if !merged.RequiredLabels.Equal(overrides.RequiredLabels) || !merged.ForbiddenFunctions.Equal(overrides.ForbiddenFunctions) {
+		t.Errorf("expected the attributes set in the resource to override the provider ones, got %+v", merged)
+	}
+	if !merged.RequiredAnnotations.Equal(provider.RequiredAnnotations) || !merged.AlertNamePattern.Equal(provider.AlertNamePattern) {
+		t.Errorf("expected the attributes not set in the resource to be the provider ones, got %+v", merged)
+	}
+	if (*rulePolicyModel)(nil).withOverrides(overrides) != overrides || provider.withOverrides(nil) != provider {
+		t.Error("expected a missing policy to be ignored")
+	}
+
+	invalid := testRulePolicyModel()
+	invalid.AlertNamePattern = types.StringValue("[A-Z")
+	invalid.ForbiddenFunctions = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("label_rewrite")})
+	if _, diags := invalid.policy(ctx, path.Root("rule_policy")); diags.ErrorsCount() != 2 {
+		t.Fatalf("expected the invalid pattern and the unknown function to be reported, got %v", diags)
+	}
+}
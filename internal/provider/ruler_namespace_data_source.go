@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/mimir/pkg/mimirtool/rules"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RulerNamespaceDataSource{}
+
+func NewRulerNamespaceDataSource() datasource.DataSource {
+	return &RulerNamespaceDataSource{}
+}
+
+// RulerNamespaceDataSource reads the rule groups of a namespace without managing it.
+type RulerNamespaceDataSource struct {
+	client mimirClientInterface
+}
+
+// RulerNamespaceDataSourceModel describes the data source data model.
+type RulerNamespaceDataSourceModel struct {
+	ID         types.String           `tfsdk:"id"`
+	Namespace  types.String           `tfsdk:"namespace"`
+	TenantID   types.String           `tfsdk:"tenant_id"`
+	ConfigYAML types.String           `tfsdk:"config_yaml"`
+	Groups     []ruleGroupSourceModel `tfsdk:"groups"`
+}
+
+// ruleGroupSourceModel describes a rule group read by the data sources.
+type ruleGroupSourceModel struct {
+	Name          types.String      `tfsdk:"name"`
+	Interval      types.String      `tfsdk:"interval"`
+	QueryOffset   types.String      `tfsdk:"query_offset"`
+	Limit         types.Int64       `tfsdk:"limit"`
+	SourceTenants types.List        `tfsdk:"source_tenants"`
+	Rules         []ruleSourceModel `tfsdk:"rules"`
+}
+
+// ruleSourceModel describes a rule read by the data sources.
+type ruleSourceModel struct {
+	Name          types.String `tfsdk:"name"`
+	Type          types.String `tfsdk:"type"`
+	Expr          types.String `tfsdk:"expr"`
+	For           types.String `tfsdk:"for"`
+	KeepFiringFor types.String `tfsdk:"keep_firing_for"`
+	Labels        types.Map    `tfsdk:"labels"`
+	Annotations   types.Map    `tfsdk:"annotations"`
+}
+
+func (d *RulerNamespaceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ruler_namespace"
+}
+
+func (d *RulerNamespaceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the rule groups of a namespace stored in Grafana Mimir. [Official documentation](https://grafana.com/docs/mimir/latest/references/http-api/#get-namespace)",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "hash",
+			},
+			"namespace": schema.StringAttribute{
+				MarkdownDescription: "The name of the namespace to read.",
+				Required:            true,
+			},
+			"tenant_id": schema.StringAttribute{
+				MarkdownDescription: "Tenant ID to read the namespace from. Overrides the provider `tenant_id`.",
+				Optional:            true,
+			},
+			"config_yaml": schema.StringAttribute{
+				MarkdownDescription: "The namespace's groups rules definition stored in Grafana Mimir as normalized YAML.",
+				Computed:            true,
+			},
+			"groups": ruleGroupsSourceAttribute(),
+		},
+	}
+}
+
+// ruleGroupsSourceAttribute returns the schema of the rule groups read by the data sources.
+func ruleGroupsSourceAttribute() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		MarkdownDescription: "The rule groups of the namespace.",
+		Computed:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					MarkdownDescription: "The name of the rule group.",
+					Computed:            true,
+				},
+				"interval": schema.StringAttribute{
+					MarkdownDescription: "How often the rules of the group are evaluated, null when the ruler evaluation interval is used.",
+					Computed:            true,
+				},
+				"query_offset": schema.StringAttribute{
+					MarkdownDescription: "The duration by which the evaluation of the rules is delayed.",
+					Computed:            true,
+				},
+				"limit": schema.Int64Attribute{
+					MarkdownDescription: "The maximum number of alerts or series the rules of the group can produce.",
+					Computed:            true,
+				},
+				"source_tenants": schema.ListAttribute{
+					MarkdownDescription: "Tenants the rule group queries data from.",
+					ElementType:         types.StringType,
+					Computed:            true,
+				},
+				"rules": schema.ListNestedAttribute{
+					MarkdownDescription: "The rules of the group.",
+					Computed:            true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"name": schema.StringAttribute{
+								MarkdownDescription: "The name of the alert or of the recorded time series.",
+								Computed:            true,
+							},
+							"type": schema.StringAttribute{
+								MarkdownDescription: "The type of the rule: `alert` or `record`.",
+								Computed:            true,
+							},
+							"expr": schema.StringAttribute{
+								MarkdownDescription: "The PromQL expression of the rule.",
+								Computed:            true,
+							},
+							"for": schema.StringAttribute{
+								MarkdownDescription: "How long the alert condition must hold before the alert fires.",
+								Computed:            true,
+							},
+							"keep_firing_for": schema.StringAttribute{
+								MarkdownDescription: "How long the alert keeps firing after its condition cleared.",
+								Computed:            true,
+							},
+							"labels": schema.MapAttribute{
+								MarkdownDescription: "The labels of the rule.",
+								ElementType:         types.StringType,
+								Computed:            true,
+							},
+							"annotations": schema.MapAttribute{
+								MarkdownDescription: "The annotations of the alert.",
+								ElementType:         types.StringType,
+								Computed:            true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RulerNamespaceDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(mimirClientInterface)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected mimirClientInterface, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+func (d *RulerNamespaceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RulerNamespaceDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := data.Namespace.ValueString()
+	normalized, ok := fetchAndNormalizeRemoteConfigYAML(ctx, tenantClient(d.client, data.TenantID), namespace, "READ", &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	var ruleNamespace rules.RuleNamespace
+	if err := yaml.Unmarshal([]byte(normalized), &ruleNamespace); err != nil {
+		resp.Diagnostics.AddError(
+			"Error while parsing namespace YAML",
+			err.Error(),
+		)
+		return
+	}
+
+	data.ID = rulerNamespaceID(data.TenantID, namespace)
+	data.ConfigYAML = types.StringValue(normalized)
+	data.Groups = ruleGroupSourceModelsFromNamespace(ruleNamespace)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// ruleGroupSourceModelsFromNamespace converts the rule groups of a namespace to
+// the structured representation exposed by the data sources.
+func ruleGroupSourceModelsFromNamespace(ruleNamespace rules.RuleNamespace) []ruleGroupSourceModel {
+	groups := make([]ruleGroupSourceModel, 0, len(ruleNamespace.Groups))
+	for _, group := range ruleNamespace.Groups {
+		// Reuse the conversion of the group blocks, which handles unset values
+		groupModel := ruleGroupModelFromRuleGroup(group)
+		sourceModel := ruleGroupSourceModel{
+			Name:          groupModel.Name,
+			Interval:      groupModel.Interval,
+			QueryOffset:   groupModel.QueryOffset,
+			Limit:         groupModel.Limit,
+			SourceTenants: groupModel.SourceTenants,
+			Rules:         make([]ruleSourceModel, 0, len(groupModel.Rules)),
+		}
+		for _, rule := range groupModel.Rules {
+			name, ruleType := rule.Alert, "alert"
+			if !rule.Record.IsNull() {
+				name, ruleType = rule.Record, "record"
+			}
+			sourceModel.Rules = append(sourceModel.Rules, ruleSourceModel{
+				Name:          name,
+				Type:          types.StringValue(ruleType),
+				Expr:          rule.Expr,
+				For:           rule.For,
+				KeepFiringFor: rule.KeepFiringFor,
+				Labels:        rule.Labels,
+				Annotations:   rule.Annotations,
+			})
+		}
+		groups = append(groups, sourceModel)
+	}
+	return groups
+}
@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+func TestAccDataSourceNamespace(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceNamespaceGroups + testAccDataSourceNamespace,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.mimirtool_ruler_namespace.demo", "id", "mimirtool_ruler_namespace.demo", "id"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespace.demo", "groups.#", "2"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespace.demo", "groups.0.name", "mimir_api_1"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespace.demo", "groups.0.rules.0.type", "record"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespace.demo", "groups.0.rules.0.name", "cluster_job:cortex_request_duration_seconds:99quantile"),
+					resource.TestCheckNoResourceAttr("data.mimirtool_ruler_namespace.demo", "groups.0.interval"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespace.demo", "groups.1.interval", "2m"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespace.demo", "groups.1.rules.0.type", "alert"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespace.demo", "groups.1.rules.0.name", "MimirRequestErrors"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespace.demo", "groups.1.rules.0.for", "15m"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespace.demo", "groups.1.rules.0.labels.severity", "critical"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespace.demo", "groups.1.rules.0.annotations.summary", "Mimir is returning errors."),
+				),
+				ConfigStateChecks: []statecheck.StateCheck{
+					SemanticYAMLStateCheck("data.mimirtool_ruler_namespace.demo", "config_yaml", testAccResourceNamespaceGroupsYaml),
+				},
+			},
+		},
+	})
+}
+
+func TestAccDataSourceNamespaceNotFound(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceNamespaceNotFound,
+				ExpectError: regexp.MustCompile("Could not read Mimir rulegroup"),
+			},
+		},
+	})
+}
+
+const testAccDataSourceNamespace = `
+data "mimirtool_ruler_namespace" "demo" {
+  namespace = mimirtool_ruler_namespace.demo.namespace
+
+  depends_on = [mimirtool_ruler_namespace.demo]
+}
+`
+
+const testAccDataSourceNamespaceNotFound = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+data "mimirtool_ruler_namespace" "demo" {
+  namespace = "does_not_exist"
+}
+`
@@ -2,13 +2,17 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/grafana/mimir/pkg/mimirtool/client"
 	"github.com/grafana/mimir/pkg/mimirtool/rules"
 	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
@@ -17,13 +21,17 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
 	"gopkg.in/yaml.v3"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
-	_ resource.Resource                = &RulerNamespaceResource{}
-	_ resource.ResourceWithImportState = &RulerNamespaceResource{}
+	_ resource.Resource                   = &RulerNamespaceResource{}
+	_ resource.ResourceWithImportState    = &RulerNamespaceResource{}
+	_ resource.ResourceWithModifyPlan     = &RulerNamespaceResource{}
+	_ resource.ResourceWithValidateConfig = &RulerNamespaceResource{}
 )
 
 func NewRulerNamespaceResource() resource.Resource {
@@ -32,17 +40,23 @@ func NewRulerNamespaceResource() resource.Resource {
 
 // RulerNamespaceResource defines the resource implementation.
 type RulerNamespaceResource struct {
-	client *client.MimirClient
+	client mimirClientInterface
+	// rulePolicy is the policy of the provider, if any
+	rulePolicy *rulePolicyModel
 }
 
 // RulerNamespaceResourceModel describes the resource data model.
 type RulerNamespaceResourceModel struct {
-	ID                       types.String `tfsdk:"id"`
-	Namespace                types.String `tfsdk:"namespace"`
-	ConfigYAML               types.String `tfsdk:"config_yaml"`
-	RemoteConfigYAML         types.String `tfsdk:"remote_config_yaml"`
-	StrictRecordingRuleCheck types.Bool   `tfsdk:"strict_recording_rule_check"`
-	RecordingRuleCheck       types.Bool   `tfsdk:"recording_rule_check"`
+	ID                       types.String                   `tfsdk:"id"`
+	Namespace                types.String                   `tfsdk:"namespace"`
+	ConfigYAML               RuleNamespaceYAMLValue         `tfsdk:"config_yaml"`
+	RemoteConfigYAML         types.String                   `tfsdk:"remote_config_yaml"`
+	StrictRecordingRuleCheck types.Bool                     `tfsdk:"strict_recording_rule_check"`
+	RecordingRuleCheck       types.Bool                     `tfsdk:"recording_rule_check"`
+	TenantID                 types.String                   `tfsdk:"tenant_id"`
+	Groups                   types.List                     `tfsdk:"group"`
+	WaitForHealthyEvaluation *waitForHealthyEvaluationModel `tfsdk:"wait_for_healthy_evaluation"`
+	RulePolicy               *rulePolicyModel               `tfsdk:"rule_policy"`
 }
 
 func (r *RulerNamespaceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -74,8 +88,9 @@ func (r *RulerNamespaceResource) Schema(ctx context.Context, _ resource.SchemaRe
 				},
 			},
 			"config_yaml": schema.StringAttribute{
-				MarkdownDescription: "User supplied namespace's groups rules definition to create in Grafana Mimir as YAML.",
-				Required:            true,
+				MarkdownDescription: "User supplied namespace's groups rules definition to create in Grafana Mimir as YAML. Formatting changes (indentation, quoting, key order, PromQL formatting) are not considered as changes of the namespace. Conflicts with `group`.",
+				CustomType:          RuleNamespaceYAMLType{},
+				Optional:            true,
 				Validators: []validator.String{
 					namespaceYAMLValidator{},
 				},
@@ -86,17 +101,29 @@ func (r *RulerNamespaceResource) Schema(ctx context.Context, _ resource.SchemaRe
 				Computed:            true,
 			},
 			"strict_recording_rule_check": schema.BoolAttribute{
-				MarkdownDescription: "Fails rules checks that do not match best practices exactly. See: https://prometheus.io/docs/practices/rules/",
+				MarkdownDescription: "Fails rules checks that do not match best practices exactly: recording rule names must have the three `level:metric:operations` parts, and a level missing the aggregation labels or operations not naming an operation of the expression are errors instead of warnings. See: https://prometheus.io/docs/practices/rules/",
 				Optional:            true,
 				Default:             booldefault.StaticBool(false),
 				Computed:            true, // https://discuss.hashicorp.com/t/why-default-attribute-must-also-be-computed/70107/2
 			},
 			"recording_rule_check": schema.BoolAttribute{
-				MarkdownDescription: "Controls whether to run recording rule checks entirely.",
+				MarkdownDescription: "Controls whether to run recording rule checks entirely. Recording rule names without colon fail the checks.",
 				Optional:            true,
 				Default:             booldefault.StaticBool(true),
 				Computed:            true, // see above
 			},
+			"tenant_id": schema.StringAttribute{
+				MarkdownDescription: "Tenant ID to manage the namespace in. Overrides the provider `tenant_id`.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"group":                       ruleGroupBlock(),
+			"wait_for_healthy_evaluation": waitForHealthyEvaluationBlock(),
+			"rule_policy":                 rulePolicyResourceBlock(),
 		},
 	}
 }
@@ -112,18 +139,21 @@ func (r *RulerNamespaceResource) Configure(ctx context.Context, req resource.Con
 		"provider_data": req.ProviderData,
 	})
 
-	client, ok := req.ProviderData.(*client.MimirClient)
+	client, ok := req.ProviderData.(mimirClientInterface)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.MimirClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected mimirClientInterface, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
 	r.client = client
+	if data, ok := req.ProviderData.(*providerData); ok {
+		r.rulePolicy = data.rulePolicy
+	}
 }
 
 func (r *RulerNamespaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -139,11 +169,18 @@ func (r *RulerNamespaceResource) Create(ctx context.Context, req resource.Create
 
 	// Extract values from the plan
 	namespace := plan.Namespace.ValueString()
-	ruleGroup := plan.ConfigYAML.ValueString()
+	ruleGroup, diags := plan.namespaceYAML(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	strictRecordingRuleCheck := plan.StrictRecordingRuleCheck.ValueBool()
 	recordingRuleCheck := plan.RecordingRuleCheck.ValueBool()
 
+	cli := tenantClient(r.client, plan.TenantID)
+
 	tflog.Debug(ctx, "CREATE - values from plan", map[string]interface{}{
+		"tenant_id":                plan.TenantID.ValueString(),
 		"namespace":                namespace,
 		"ruleGroup":                ruleGroup,
 		"strictRecordingRuleCheck": strictRecordingRuleCheck,
@@ -161,31 +198,48 @@ func (r *RulerNamespaceResource) Create(ctx context.Context, req resource.Create
 	}
 
 	if recordingRuleCheck {
-		err = checkRecordingRules(ruleNamespace, strictRecordingRuleCheck)
-		if err != nil {
-			// TODO: add a more explicit error message
-			resp.Diagnostics.AddError(
-				"Failed to check recording rule group",
-				err.Error(),
-			)
+		resp.Diagnostics.Append(checkRecordingRules(ruleNamespace.Groups, strictRecordingRuleCheck)...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
 	}
 
+	// Values unknown at plan time are only checked against the policy now
+	r.enforceRulePolicy(ctx, plan, ruleNamespace.Groups, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Snapshot the namespace so that it can be restored if a group fails to be created
+	priorGroups, err := listRuleGroups(ctx, cli, namespace)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to read existing namespace",
+			err.Error(),
+		)
+		return
+	}
+
+	priorEvaluations, ok := r.snapshotRuleGroupEvaluations(ctx, cli, plan, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
 	// Create rule groups in Mimir
-	if err := createAllRuleGroups(ctx, r.client, namespace, ruleNamespace.Groups); err != nil {
+	if err := createAllRuleGroups(ctx, cli, namespace, ruleNamespace.Groups); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to create rule groups",
 			err.Error(),
 		)
+		rollbackRuleGroups(ctx, cli, namespace, priorGroups, &resp.Diagnostics)
 		return
 	}
 
 	// Set ID
-	plan.ID = types.StringValue(hash(namespace))
+	plan.ID = rulerNamespaceID(plan.TenantID, namespace)
 
 	// Always fetch canonical YAML from backend and store in state
-	normalized, ok := fetchAndNormalizeRemoteConfigYAML(ctx, r.client, namespace, "CREATE", &resp.Diagnostics)
+	normalized, ok := fetchAndNormalizeRemoteConfigYAML(ctx, cli, namespace, "CREATE", &resp.Diagnostics)
 	if !ok {
 		return
 	}
@@ -193,6 +247,17 @@ func (r *RulerNamespaceResource) Create(ctx context.Context, req resource.Create
 
 	// Save the state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The groups are stored, failed evaluations are only warnings: an error
+	// would taint the namespace, and the next apply would delete it
+	if plan.WaitForHealthyEvaluation != nil {
+		wait := *plan.WaitForHealthyEvaluation
+		wait.Severity = types.StringValue("warning")
+		waitForHealthyEvaluation(ctx, cli, namespace, ruleNamespace.Groups, priorEvaluations, &wait, &resp.Diagnostics)
+	}
 }
 
 func (r *RulerNamespaceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -205,17 +270,207 @@ func (r *RulerNamespaceResource) Read(ctx context.Context, req resource.ReadRequ
 
 	namespace := state.Namespace.ValueString()
 
-	// Use the same helper as Create/Update for fetching and normalizing YAML
-	normalized, ok := fetchAndNormalizeRemoteConfigYAML(ctx, r.client, namespace, "READ", &resp.Diagnostics)
+	remoteGroups, err := listRuleGroups(ctx, tenantClient(r.client, state.TenantID), namespace)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Mimir RuleGroup after READ",
+			fmt.Sprintf("Could not read Mimir rulegroup for namespace %q: %s", namespace, err.Error()),
+		)
+		return
+	}
+	if len(remoteGroups) == 0 {
+		resp.Diagnostics.AddWarning(
+			"Namespace not found",
+			fmt.Sprintf("Namespace %q no longer exists in Grafana Mimir and will be re-created.", namespace),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Use the same helper as Create/Update for normalizing YAML
+	normalized, ok := normalizeRemoteConfigYAML(ctx, namespace, remoteGroups, "READ", &resp.Diagnostics)
 	if !ok {
 		return
 	}
 	state.RemoteConfigYAML = types.StringValue(normalized)
-	state.ID = types.StringValue(hash(namespace))
+
+	// Detect changes made outside of Terraform. When the namespace drifted, the
+	// remote definition is stored in place of the configured one so that the plan
+	// shows an update, otherwise the user's formatting is kept.
+	switch {
+	case hasRuleGroupBlocks(state.Groups):
+		configured, diags := state.namespaceYAML(ctx)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if drift := detectNamespaceDrift(configured, normalized); len(drift) > 0 {
+			addNamespaceDriftWarning(&resp.Diagnostics, namespace, "the group blocks", drift)
+			groups, diags := ruleGroupModelsFromRuleGroups(ctx, remoteGroups)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			state.Groups = groups
+		}
+	case !state.ConfigYAML.IsNull():
+		if drift := detectNamespaceDrift(state.ConfigYAML.ValueString(), normalized); len(drift) > 0 {
+			addNamespaceDriftWarning(&resp.Diagnostics, namespace, "config_yaml", drift)
+			state.ConfigYAML = NewRuleNamespaceYAMLValue(normalized)
+		}
+	}
+	state.ID = rulerNamespaceID(state.TenantID, namespace)
 	tflog.Debug(ctx, "Read: setting state.ID", map[string]interface{}{"id": state.ID.ValueString()})
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
+// addNamespaceDriftWarning reports the differences between the rule groups stored
+// in Mimir and the configured ones.
+func addNamespaceDriftWarning(diagnostics *diag.Diagnostics, namespace, source string, drift []string) {
+	diagnostics.AddWarning(
+		"Namespace drifted from its configuration",
+		fmt.Sprintf("The rule groups of namespace %q stored in Grafana Mimir differ from %s and will be updated:\n  - %s", namespace, source, strings.Join(drift, "\n  - ")),
+	)
+}
+
+// ValidateConfig ensures the namespace is defined either by config_yaml or by
+// group blocks, and validates the rule groups defined by the blocks.
+func (r *RulerNamespaceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config RulerNamespaceResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config.WaitForHealthyEvaluation.validate(&resp.Diagnostics)
+	if config.RulePolicy != nil && config.RulePolicy.isKnown(ctx) {
+		_, diags := config.RulePolicy.policy(ctx, path.Root("rule_policy"))
+		resp.Diagnostics.Append(diags...)
+	}
+
+	groupsSet := config.Groups.IsUnknown() || len(config.Groups.Elements()) > 0
+	switch {
+	case groupsSet && !config.ConfigYAML.IsNull():
+		resp.Diagnostics.AddAttributeError(
+			path.Root("group"),
+			"Conflicting namespace definitions",
+			"Only one of config_yaml or group blocks can be set.",
+		)
+		return
+	case !groupsSet && config.ConfigYAML.IsNull():
+		resp.Diagnostics.AddAttributeError(
+			path.Root("config_yaml"),
+			"Missing namespace definition",
+			"One of config_yaml or group blocks must be set.",
+		)
+		return
+	}
+
+	// Values of the blocks may only be known at apply time
+	if !groupsSet || !isFullyKnown(ctx, config.Groups) {
+		return
+	}
+	groups, diags := ruleGroupsFromList(ctx, config.Groups)
+	if diags.HasError() {
+		for _, d := range diags.Errors() {
+			resp.Diagnostics.AddAttributeError(path.Root("group"), d.Summary(), d.Detail())
+		}
+		return
+	}
+	if exprErrs := ruleExpressionErrors(groups); len(exprErrs) > 0 {
+		for _, exprErr := range exprErrs {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("group").AtListIndex(exprErr.group).AtName("rule").AtListIndex(exprErr.rule).AtName("expr"),
+				"Invalid PromQL expression",
+				exprErr.detail,
+			)
+		}
+		return
+	}
+	if _, err := ruleNamespaceFromGroups(ctx, groups); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("group"),
+			"Invalid rule groups",
+			fmt.Sprintf("Namespace definition is not valid: %s", err.Error()),
+		)
+	}
+}
+
+// namespaceYAML returns the YAML definition of the namespace, either config_yaml
+// or the rule groups defined by the group blocks.
+func (m RulerNamespaceResourceModel) namespaceYAML(ctx context.Context) (string, diag.Diagnostics) {
+	if !hasRuleGroupBlocks(m.Groups) {
+		return m.ConfigYAML.ValueString(), nil
+	}
+	groups, diags := ruleGroupsFromList(ctx, m.Groups)
+	if diags.HasError() {
+		return "", diags
+	}
+	configYAML, err := ruleGroupsYAML(groups)
+	if err != nil {
+		diags.AddError("Invalid rule groups", err.Error())
+	}
+	return configYAML, diags
+}
+
+func hasRuleGroupBlocks(groups types.List) bool {
+	return !groups.IsNull() && !groups.IsUnknown() && len(groups.Elements()) > 0
+}
+
+// ModifyPlan enforces the rule policy, and keeps remote_config_yaml known when
+// the namespace definition only changes in formatting, or is moved between
+// config_yaml and group blocks, as such a change does not modify the rule
+// groups stored in Mimir.
+func (r *RulerNamespaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state RulerNamespaceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The provider policy is only known once the provider is configured, hence
+	// the policy is enforced here rather than in ValidateConfig
+	if !plan.ConfigYAML.IsUnknown() && isFullyKnown(ctx, plan.Groups) {
+		// Invalid definitions are reported by ValidateConfig
+		if configYAML, diags := plan.namespaceYAML(ctx); !diags.HasError() {
+			if ruleNamespace, err := getRuleNamespaceFromYAML(ctx, configYAML); err == nil {
+				r.enforceRulePolicy(ctx, plan, ruleNamespace.Groups, &resp.Diagnostics)
+			}
+		}
+	}
+
+	// Nothing more to do on create
+	if resp.Diagnostics.HasError() || req.State.Raw.IsNull() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.ConfigYAML.IsUnknown() || !isFullyKnown(ctx, plan.Groups) || !plan.RemoteConfigYAML.IsUnknown() ||
+		!plan.Namespace.Equal(state.Namespace) || !plan.TenantID.Equal(state.TenantID) {
+		return
+	}
+	// Invalid definitions are reported by ValidateConfig
+	planned, diags := plan.namespaceYAML(ctx)
+	if diags.HasError() {
+		return
+	}
+	prior, diags := state.namespaceYAML(ctx)
+	if diags.HasError() {
+		return
+	}
+	if ruleNamespaceYAMLEqual(prior, planned) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("remote_config_yaml"), state.RemoteConfigYAML)...)
+	}
+}
+
 func (r *RulerNamespaceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	tflog.Debug(ctx, "DELETE - init")
 	var state RulerNamespaceResourceModel
@@ -230,7 +485,7 @@ func (r *RulerNamespaceResource) Delete(ctx context.Context, req resource.Delete
 		"state_config_yaml": state.ConfigYAML.ValueString(),
 	})
 
-	err := r.client.DeleteNamespace(ctx, namespace)
+	err := tenantClient(r.client, state.TenantID).DeleteNamespace(ctx, namespace)
 
 	if err != nil && !strings.Contains(err.Error(), "not found") {
 		resp.Diagnostics.AddError(
@@ -249,16 +504,25 @@ func (r *RulerNamespaceResource) Delete(ctx context.Context, req resource.Delete
 
 func (r *RulerNamespaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	tflog.Debug(ctx, "IMPORT STATE - init")
-	// The import ID is the namespace name
-	namespace := req.ID
+	// The import ID is the namespace name, optionally prefixed by the tenant ID
+	tenantID, namespace := splitTenantImportID(req.ID)
+	if namespace == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form [tenant_id=<tenant_id>:]<namespace>, got %q.", req.ID),
+		)
+		return
+	}
 
 	// Create a state with the namespace set
 	var state RulerNamespaceResourceModel
 	state.Namespace = types.StringValue(namespace)
-	state.ID = types.StringValue(hash(namespace))
+	state.TenantID = tenantID
+	state.ID = rulerNamespaceID(tenantID, namespace)
+	state.Groups = types.ListNull(ruleGroupListType.ElemType)
 
 	// Fetch backend rules to update the state
-	normalized, ok := fetchAndNormalizeRemoteConfigYAML(ctx, r.client, namespace, "IMPORT", &resp.Diagnostics)
+	normalized, ok := fetchAndNormalizeRemoteConfigYAML(ctx, tenantClient(r.client, tenantID), namespace, "IMPORT", &resp.Diagnostics)
 	if !ok {
 		return
 	}
@@ -269,6 +533,33 @@ func (r *RulerNamespaceResource) ImportState(ctx context.Context, req resource.I
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
+// enforceRulePolicy reports, on the namespace definition, every rule which does
+// not follow the provider policy overridden by the resource one.
+func (r *RulerNamespaceResource) enforceRulePolicy(ctx context.Context, m RulerNamespaceResourceModel, groups []rwrulefmt.RuleGroup, diagnostics *diag.Diagnostics) {
+	// The lines of the YAML generated from group blocks mean nothing to the user
+	if hasRuleGroupBlocks(m.Groups) {
+		if blockGroups, diags := ruleGroupsFromList(ctx, m.Groups); !diags.HasError() {
+			groups = blockGroups
+		}
+	}
+	enforceRulePolicy(ctx, r.rulePolicy, m.RulePolicy, groups, func(violation rulePolicyViolation) path.Path {
+		if hasRuleGroupBlocks(m.Groups) {
+			return path.Root("group").AtListIndex(violation.group).AtName("rule").AtListIndex(violation.rule)
+		}
+		return path.Root("config_yaml")
+	}, diagnostics)
+}
+
+// rulerNamespaceID returns the resource ID: the hash of the namespace, qualified
+// with the tenant when the resource overrides the provider one. They are
+// separated by a NUL byte as both may contain slashes.
+func rulerNamespaceID(tenantID types.String, namespace string) types.String {
+	if tenantID.ValueString() == "" {
+		return types.StringValue(hash(namespace))
+	}
+	return types.StringValue(hash(tenantID.ValueString() + "\x00" + namespace))
+}
+
 func getRuleNamespaceFromYAML(_ context.Context, configYAML string) (rules.RuleNamespace, error) {
 	var ruleNamespace rules.RuleNamespace
 	// We pass only one ruleGroup while ParseBytes return an array, we only need the first element
@@ -286,14 +577,6 @@ func getRuleNamespaceFromYAML(_ context.Context, configYAML string) (rules.RuleN
 	return ruleNamespace, fmt.Errorf("no namespace definition found")
 }
 
-func checkRecordingRules(ruleNamespace rules.RuleNamespace, strict bool) error {
-	invalidRulesCount := ruleNamespace.CheckRecordingRules(strict)
-	if invalidRulesCount > 0 {
-		return fmt.Errorf("namespace contains %d rules that don't match the requirements", invalidRulesCount)
-	}
-	return nil
-}
-
 // Borrowed from https://github.com/grafana/terraform-provider-grafana/blob/main/internal/resources/grafana/resource_dashboard.go
 func normalizeNamespaceYAML(config any) (string, int, int, error) {
 	configYAML := config.(string)
@@ -322,64 +605,101 @@ func (r *RulerNamespaceResource) Update(ctx context.Context, req resource.Update
 	}
 
 	namespace := plan.Namespace.ValueString()
-	ruleGroup := plan.ConfigYAML.ValueString()
+	ruleGroup, diags := plan.namespaceYAML(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	strictRecordingRuleCheck := plan.StrictRecordingRuleCheck.ValueBool()
 	recordingRuleCheck := plan.RecordingRuleCheck.ValueBool()
 
-	// Delete the current namespace to replace it
-	err := r.client.DeleteNamespace(ctx, namespace)
+	ruleNamespace, err := getRuleNamespaceFromYAML(ctx, ruleGroup)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Failed to delete existing namespace",
+			"Failed to parse rule group YAML",
 			err.Error(),
 		)
 		return
 	}
 
-	ruleNamespace, err := getRuleNamespaceFromYAML(ctx, ruleGroup)
+	if recordingRuleCheck {
+		resp.Diagnostics.Append(checkRecordingRules(ruleNamespace.Groups, strictRecordingRuleCheck)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Values unknown at plan time are only checked against the policy now
+	r.enforceRulePolicy(ctx, plan, ruleNamespace.Groups, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Fetch the groups currently stored in Mimir so that only the groups which
+	// actually changed are written and the namespace is never left empty
+	cli := tenantClient(r.client, plan.TenantID)
+	remoteGroups, err := listRuleGroups(ctx, cli, namespace)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Failed to parse rule group YAML",
+			"Failed to read existing namespace",
 			err.Error(),
 		)
 		return
 	}
 
-	if recordingRuleCheck {
-		err = checkRecordingRules(ruleNamespace, strictRecordingRuleCheck)
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Failed to check recording rule group",
-				err.Error(),
-			)
-			return
-		}
+	priorEvaluations, ok := r.snapshotRuleGroupEvaluations(ctx, cli, plan, &resp.Diagnostics)
+	if !ok {
+		return
 	}
 
-	// Create all rule groups for the namespace
-	if err := createAllRuleGroups(ctx, r.client, namespace, ruleNamespace.Groups); err != nil {
+	if err := applyRuleGroups(ctx, cli, namespace, remoteGroups, ruleNamespace.Groups); err != nil {
 		resp.Diagnostics.AddError(
-			"Failed to create rule groups",
+			"Failed to update rule groups",
 			err.Error(),
 		)
+		rollbackRuleGroups(ctx, cli, namespace, remoteGroups, &resp.Diagnostics)
 		return
 	}
 
 	// Set the ID
-	plan.ID = types.StringValue(hash(namespace))
+	plan.ID = rulerNamespaceID(plan.TenantID, namespace)
 
 	// Fetch backend rules
-	normalized, ok := fetchAndNormalizeRemoteConfigYAML(ctx, r.client, namespace, "UPDATE", &resp.Diagnostics)
+	normalized, ok := fetchAndNormalizeRemoteConfigYAML(ctx, cli, namespace, "UPDATE", &resp.Diagnostics)
 	if !ok {
 		return
 	}
 	plan.RemoteConfigYAML = types.StringValue(normalized)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.WaitForHealthyEvaluation != nil {
+		waitForHealthyEvaluation(ctx, cli, namespace, ruleNamespace.Groups, priorEvaluations, plan.WaitForHealthyEvaluation, &resp.Diagnostics)
+	}
+}
+
+// snapshotRuleGroupEvaluations snapshots the evaluations of the namespace before it
+// is written, when the resource waits for the groups to be evaluated.
+func (r *RulerNamespaceResource) snapshotRuleGroupEvaluations(ctx context.Context, cli mimirClientInterface, plan RulerNamespaceResourceModel, diagnostics *diag.Diagnostics) (map[string]time.Time, bool) {
+	if plan.WaitForHealthyEvaluation == nil {
+		return nil, true
+	}
+	evaluations, err := lastRuleGroupEvaluations(ctx, cli, plan.Namespace.ValueString())
+	if err != nil {
+		diagnostics.AddError(
+			"Failed to read rules status",
+			fmt.Sprintf("Could not read the status of the rules of namespace %q: %s", plan.Namespace.ValueString(), err.Error()),
+		)
+		return nil, false
+	}
+	return evaluations, true
 }
 
 // Create rule groups in Mimir
-func createAllRuleGroups(ctx context.Context, client *client.MimirClient, namespace string, groups []rwrulefmt.RuleGroup) error {
+func createAllRuleGroups(ctx context.Context, client mimirClientInterface, namespace string, groups []rwrulefmt.RuleGroup) error {
 	for _, group := range groups {
 		if err := client.CreateRuleGroup(ctx, namespace, group); err != nil {
 			return err
@@ -388,10 +708,210 @@ func createAllRuleGroups(ctx context.Context, client *client.MimirClient, namesp
 	return nil
 }
 
+// rollbackRuleGroups restores the namespace to the groups it contained before
+// it was modified: removed and modified groups are re-created and newly added
+// groups are deleted. The outcome is reported in diagnostics.
+func rollbackRuleGroups(ctx context.Context, cli mimirClientInterface, namespace string, prior []rwrulefmt.RuleGroup, diagnostics *diag.Diagnostics) {
+	tflog.Info(ctx, "Rolling back namespace", map[string]interface{}{"namespace": namespace, "groups": len(prior)})
+
+	current, err := listRuleGroups(ctx, cli, namespace)
+	if err == nil {
+		err = applyRuleGroups(ctx, cli, namespace, current, prior)
+	}
+	if err != nil {
+		diagnostics.AddError(
+			"Failed to roll back namespace",
+			fmt.Sprintf("Namespace %q could not be restored to its previous state and may be partially applied: %s", namespace, err.Error()),
+		)
+		return
+	}
+	diagnostics.AddWarning(
+		"Namespace rolled back",
+		fmt.Sprintf("Namespace %q has been restored to the %d rule group(s) it contained before the failed operation.", namespace, len(prior)),
+	)
+}
+
+// listRuleGroups returns the rule groups stored in Mimir for the namespace.
+// A namespace which does not exist is reported as having no groups.
+func listRuleGroups(ctx context.Context, cli mimirClientInterface, namespace string) ([]rwrulefmt.RuleGroup, error) {
+	ruleSet, err := cli.ListRules(ctx, namespace)
+	if err != nil {
+		if errors.Is(err, client.ErrResourceNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ruleSet[namespace], nil
+}
+
+// applyRuleGroups brings the namespace from the current groups to the desired ones.
+// New and modified groups are upserted first, then the groups which are no longer
+// desired are deleted, so unchanged groups are never touched.
+func applyRuleGroups(ctx context.Context, cli mimirClientInterface, namespace string, current, desired []rwrulefmt.RuleGroup) error {
+	upserts, deletes := diffRuleGroups(current, desired)
+
+	tflog.Debug(ctx, "Applying rule group changes", map[string]interface{}{
+		"namespace": namespace,
+		"upserts":   len(upserts),
+		"deletes":   deletes,
+	})
+
+	for _, group := range upserts {
+		if err := cli.CreateRuleGroup(ctx, namespace, group); err != nil {
+			return fmt.Errorf("failed to write rule group %q: %w", group.Name, err)
+		}
+	}
+	for _, name := range deletes {
+		if err := cli.DeleteRuleGroup(ctx, namespace, name); err != nil && !errors.Is(err, client.ErrResourceNotFound) {
+			return fmt.Errorf("failed to delete rule group %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// diffRuleGroups returns the desired groups which are missing from or differ with
+// the current ones, and the names of the current groups which are not desired anymore.
+func diffRuleGroups(current, desired []rwrulefmt.RuleGroup) ([]rwrulefmt.RuleGroup, []string) {
+	currentByName := make(map[string]rwrulefmt.RuleGroup, len(current))
+	for _, group := range current {
+		currentByName[group.Name] = group
+	}
+
+	var upserts []rwrulefmt.RuleGroup
+	desiredNames := make(map[string]struct{}, len(desired))
+	for _, group := range desired {
+		desiredNames[group.Name] = struct{}{}
+		if existing, ok := currentByName[group.Name]; ok && ruleGroupsEqual(group, existing) {
+			continue
+		}
+		upserts = append(upserts, group)
+	}
+
+	var deletes []string
+	for _, group := range current {
+		if _, ok := desiredNames[group.Name]; !ok {
+			deletes = append(deletes, group.Name)
+		}
+	}
+	return upserts, deletes
+}
+
+// ruleGroupsEqual reports whether two rule groups are equivalent, regardless of
+// the formatting of their PromQL expressions.
+// rules.CompareGroups ignores a few fields, they are compared here as well.
+func ruleGroupsEqual(a, b rwrulefmt.RuleGroup) bool {
+	a, b = withFormattedExpressions(a), withFormattedExpressions(b)
+	if rules.CompareGroups(a, b) != nil {
+		return false
+	}
+	if a.Limit != b.Limit || a.AlignEvaluationTimeOnInterval != b.AlignEvaluationTimeOnInterval {
+		return false
+	}
+	for i := range a.Rules {
+		if a.Rules[i].KeepFiringFor != b.Rules[i].KeepFiringFor {
+			return false
+		}
+	}
+	return true
+}
+
+// detectNamespaceDrift describes how the namespace stored in Mimir differs from
+// the configured one. Both definitions are normalized the same way beforehand
+// so that formatting differences are not reported.
+func detectNamespaceDrift(configYAML, remoteYAML string) []string {
+	desired, err := parseNormalizedNamespace(configYAML)
+	if err != nil {
+		// config_yaml is validated at plan time, nothing to compare against
+		return nil
+	}
+	remote, err := parseNormalizedNamespace(remoteYAML)
+	if err != nil {
+		return nil
+	}
+	return describeRuleGroupsDrift(remote.Groups, desired.Groups)
+}
+
+func parseNormalizedNamespace(configYAML string) (rules.RuleNamespace, error) {
+	var ruleNamespace rules.RuleNamespace
+	normalized, _, _, err := normalizeNamespaceYAML(configYAML)
+	if err != nil {
+		return ruleNamespace, err
+	}
+	err = yaml.Unmarshal([]byte(normalized), &ruleNamespace)
+	return ruleNamespace, err
+}
+
+// describeRuleGroupsDrift returns a description of every group and rule which
+// differs between the remote and the desired groups.
+func describeRuleGroupsDrift(remote, desired []rwrulefmt.RuleGroup) []string {
+	remoteByName := make(map[string]rwrulefmt.RuleGroup, len(remote))
+	for _, group := range remote {
+		remoteByName[group.Name] = group
+	}
+
+	var drift []string
+	upserts, deletes := diffRuleGroups(remote, desired)
+	for _, group := range upserts {
+		remoteGroup, ok := remoteByName[group.Name]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("group %q is missing from Mimir", group.Name))
+			continue
+		}
+
+		remoteSettings, desiredSettings := remoteGroup, group
+		remoteSettings.Rules, desiredSettings.Rules = nil, nil
+		if !ruleGroupsEqual(remoteSettings, desiredSettings) {
+			drift = append(drift, fmt.Sprintf("group %q: group settings differ", group.Name))
+		}
+		for i := 0; i < max(len(remoteGroup.Rules), len(group.Rules)); i++ {
+			switch {
+			case i >= len(remoteGroup.Rules):
+				drift = append(drift, fmt.Sprintf("group %q: rule %d (%s) is missing from Mimir", group.Name, i, ruleDisplayName(group.Rules[i])))
+			case i >= len(group.Rules):
+				drift = append(drift, fmt.Sprintf("group %q: rule %d (%s) only exists in Mimir", group.Name, i, ruleDisplayName(remoteGroup.Rules[i])))
+			case !rulesEqual(remoteGroup.Rules[i], group.Rules[i]):
+				drift = append(drift, fmt.Sprintf("group %q: rule %d (%s) differs", group.Name, i, ruleDisplayName(group.Rules[i])))
+			}
+		}
+	}
+	for _, name := range deletes {
+		drift = append(drift, fmt.Sprintf("group %q only exists in Mimir", name))
+	}
+	return drift
+}
+
+// withFormattedExpressions returns a copy of the group whose PromQL expressions
+// are formatted by the PromQL parser. Invalid expressions are left untouched.
+func withFormattedExpressions(group rwrulefmt.RuleGroup) rwrulefmt.RuleGroup {
+	group.Rules = slices.Clone(group.Rules)
+	for i, rule := range group.Rules {
+		if expr, err := parser.ParseExpr(rule.Expr.Value); err == nil {
+			group.Rules[i].Expr.Value = expr.String()
+		}
+	}
+	return group
+}
+
+// rulesEqual reports whether two rules are equivalent.
+func rulesEqual(a, b rulefmt.RuleNode) bool {
+	return ruleGroupsEqual(
+		rwrulefmt.RuleGroup{RuleGroup: rulefmt.RuleGroup{Rules: []rulefmt.RuleNode{a}}},
+		rwrulefmt.RuleGroup{RuleGroup: rulefmt.RuleGroup{Rules: []rulefmt.RuleNode{b}}},
+	)
+}
+
+// ruleDisplayName returns the type and name of a rule, e.g. alert "HighCPU".
+func ruleDisplayName(rule rulefmt.RuleNode) string {
+	if rule.Record.Value != "" {
+		return fmt.Sprintf("record %q", rule.Record.Value)
+	}
+	return fmt.Sprintf("alert %q", rule.Alert.Value)
+}
+
 // Helper function for fetching and normalizing the remote config YAML
 func fetchAndNormalizeRemoteConfigYAML(
 	ctx context.Context,
-	client *client.MimirClient,
+	client mimirClientInterface,
 	namespace string,
 	op string,
 	diagnostics *diag.Diagnostics,
@@ -405,15 +925,22 @@ func fetchAndNormalizeRemoteConfigYAML(
 		return "", false
 	}
 
-	tflog.Trace(ctx, op+": raw value for remoteNamespaceRuleGroup", map[string]interface{}{"remoteNamespaceRuleGroup": remoteNamespaceRuleGroup})
+	return normalizeRemoteConfigYAML(ctx, namespace, remoteNamespaceRuleGroup[namespace], op, diagnostics)
+}
 
-	// Mimir top level key is the namespace name while in the YAML definition the top level key is groups
-	// Let's rename the key to be able to have a nice difference
-	// TODO: might not be needed anymore since we have introduced the remote_config_yaml attribute
-	remoteNamespaceRuleGroup["groups"] = remoteNamespaceRuleGroup[namespace]
-	delete(remoteNamespaceRuleGroup, namespace)
+// normalizeRemoteConfigYAML returns the normalized YAML of the rule groups
+// read from Mimir for the namespace.
+func normalizeRemoteConfigYAML(
+	ctx context.Context,
+	namespace string,
+	groups []rwrulefmt.RuleGroup,
+	op string,
+	diagnostics *diag.Diagnostics,
+) (string, bool) {
+	tflog.Trace(ctx, op+": raw value for remote rule groups", map[string]interface{}{"namespace": namespace, "groups": groups})
 
-	tflog.Debug(ctx, op+": after removing the namespace key", map[string]interface{}{"remoteNamespaceRuleGroupAfterRemovingNamespaceKey": remoteNamespaceRuleGroup})
+	// Mimir top level key is the namespace name while in the YAML definition the top level key is groups
+	remoteNamespaceRuleGroup := map[string][]rwrulefmt.RuleGroup{"groups": groups}
 
 	remoteConfigYAML, err := yaml.Marshal(remoteNamespaceRuleGroup)
 	if err != nil {
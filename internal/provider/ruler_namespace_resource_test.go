@@ -4,15 +4,25 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/prometheus/prometheus/model/rulefmt"
 	"gopkg.in/yaml.v3"
 )
 
@@ -82,6 +92,13 @@ func TestAccResourceNamespace(t *testing.T) {
 					SemanticYAMLStateCheck("mimirtool_ruler_namespace.demo", "remote_config_yaml", testAccResourceNamespaceYamlAfterUpdate),
 				},
 			},
+			{
+				// Removing a group only deletes that group
+				Config: testAccResourceNamespace,
+				ConfigStateChecks: []statecheck.StateCheck{
+					SemanticYAMLStateCheck("mimirtool_ruler_namespace.demo", "remote_config_yaml", testAccResourceNamespaceYaml),
+				},
+			},
 			{
 				ResourceName:      "mimirtool_ruler_namespace.demo",
 				ImportStateId:     "demo",
@@ -94,6 +111,330 @@ func TestAccResourceNamespace(t *testing.T) {
 	})
 }
 
+func TestRulerNamespaceID(t *testing.T) {
+	// The namespace a/b of the provider tenant is not the namespace b of tenant a
+	if rulerNamespaceID(types.StringNull(), "a/b").Equal(rulerNamespaceID(types.StringValue("a"), "b")) {
+		t.Error("expected namespaces of different tenants to have different IDs")
+	}
+	if !rulerNamespaceID(types.StringNull(), "demo").Equal(types.StringValue(hash("demo"))) {
+		t.Error("expected the ID of a namespace of the provider tenant to be the hash of its name")
+	}
+}
+
+func TestDiffRuleGroups(t *testing.T) {
+	rulesYAML, err := os.ReadFile("testdata/rules.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := getRuleNamespaceFromYAML(context.Background(), string(rulesYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired, err := getRuleNamespaceFromYAML(context.Background(), string(rulesYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing changed
+	upserts, deletes := diffRuleGroups(current.Groups, desired.Groups)
+	if len(upserts) != 0 || len(deletes) != 0 {
+		t.Fatalf("expected no changes, got %d upserts and deletes %v", len(upserts), deletes)
+	}
+
+	// Only the formatting of the expressions changed
+	spacingYAML, err := os.ReadFile("testdata/rules2_spacing.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reformatted, err := getRuleNamespaceFromYAML(context.Background(), string(spacingYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	upserts, deletes = diffRuleGroups(current.Groups, reformatted.Groups)
+	if len(upserts) != 0 || len(deletes) != 0 {
+		t.Fatalf("expected no changes, got %d upserts and deletes %v", len(upserts), deletes)
+	}
+
+	// Adding a group only upserts that group
+	added := desired.Groups[0]
+	added.Name = "mimir_api_2"
+	upserts, deletes = diffRuleGroups(current.Groups, append(desired.Groups, added))
+	if len(upserts) != 1 || upserts[0].Name != "mimir_api_2" || len(deletes) != 0 {
+		t.Fatalf("expected mimir_api_2 to be upserted, got %d upserts and deletes %v", len(upserts), deletes)
+	}
+
+	// Changing a rule upserts the group
+	changed := desired.Groups[0]
+	changed.Limit = 10
+	upserts, deletes = diffRuleGroups(current.Groups, []rwrulefmt.RuleGroup{changed})
+	if len(upserts) != 1 || len(deletes) != 0 {
+		t.Fatalf("expected mimir_api_1 to be upserted, got %d upserts and deletes %v", len(upserts), deletes)
+	}
+
+	// Removing a group only deletes that group
+	upserts, deletes = diffRuleGroups(append(current.Groups, added), desired.Groups)
+	if len(upserts) != 0 || !reflect.DeepEqual(deletes, []string{"mimir_api_2"}) {
+		t.Fatalf("expected mimir_api_2 to be deleted, got %d upserts and deletes %v", len(upserts), deletes)
+	}
+}
+
+func TestRollbackRuleGroups(t *testing.T) {
+	ctx := context.Background()
+	rulesYAML, err := os.ReadFile("testdata/rules.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules2YAML, err := os.ReadFile("testdata/rules2.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prior, err := getRuleNamespaceFromYAML(ctx, string(rulesYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired, err := getRuleNamespaceFromYAML(ctx, string(rules2YAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Update: mimir_api_1 is rewritten before mimir_api_2 fails
+	cli := newFakeMimirClient()
+	cli.namespaces["demo"] = append([]rwrulefmt.RuleGroup(nil), prior.Groups...)
+	cli.failGroup = "mimir_api_2"
+	if err := applyRuleGroups(ctx, cli, "demo", prior.Groups, desired.Groups); err == nil {
+		t.Fatal("expected applyRuleGroups to fail")
+	}
+	var diags diag.Diagnostics
+	rollbackRuleGroups(ctx, cli, "demo", prior.Groups, &diags)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("expected a single rollback warning, got %v", diags)
+	}
+	upserts, deletes := diffRuleGroups(cli.namespaces["demo"], prior.Groups)
+	if len(upserts) != 0 || len(deletes) != 0 {
+		t.Fatalf("expected namespace to be restored, got %#v", cli.namespaces["demo"])
+	}
+
+	// Create: mimir_api_1 is created before mimir_api_2 fails
+	cli = newFakeMimirClient()
+	cli.failGroup = "mimir_api_2"
+	if err := createAllRuleGroups(ctx, cli, "demo", desired.Groups); err == nil {
+		t.Fatal("expected createAllRuleGroups to fail")
+	}
+	diags = nil
+	rollbackRuleGroups(ctx, cli, "demo", nil, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected rollback error: %v", diags)
+	}
+	if _, ok := cli.namespaces["demo"]; ok {
+		t.Fatalf("expected namespace to be removed, got %#v", cli.namespaces["demo"])
+	}
+}
+
+func TestDetectNamespaceDrift(t *testing.T) {
+	rulesYAML, err := os.ReadFile("testdata/rules2.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spacingYAML, err := os.ReadFile("testdata/rules2_spacing.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Formatting differences are not drift
+	if drift := detectNamespaceDrift(string(spacingYAML), testAccResourceNamespaceYaml); len(drift) != 0 {
+		t.Fatalf("expected no drift, got %v", drift)
+	}
+
+	drift := detectNamespaceDrift(string(rulesYAML), testAccResourceNamespaceYamlDrifted)
+	expected := []string{
+		`group "mimir_api_1": rule 1 (record "cluster_job:cortex_request_duration_seconds:50quantile") differs`,
+		`group "mimir_api_2" is missing from Mimir`,
+		`group "manual" only exists in Mimir`,
+	}
+	if !reflect.DeepEqual(drift, expected) {
+		t.Fatalf("unexpected drift\nExpected: %q\nActual: %q", expected, drift)
+	}
+}
+
+func TestAccResourceNamespaceDrift(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceNamespace,
+			},
+			{
+				// A group is added outside of Terraform
+				PreConfig: func() {
+					group := rwrulefmt.RuleGroup{RuleGroup: rulefmt.RuleGroup{Name: "manual"}}
+					group.Rules = []rulefmt.RuleNode{{
+						Record: yaml.Node{Kind: yaml.ScalarNode, Value: "job:up:sum"},
+						Expr:   yaml.Node{Kind: yaml.ScalarNode, Value: "sum by (job) (up)"},
+					}}
+					if err := testAccMimirClient(t).CreateRuleGroup(context.Background(), "demo", group); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccResourceNamespace,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccResourceNamespace,
+				ConfigStateChecks: []statecheck.StateCheck{
+					SemanticYAMLStateCheck("mimirtool_ruler_namespace.demo", "remote_config_yaml", testAccResourceNamespaceYaml),
+				},
+			},
+			{
+				// The namespace is deleted outside of Terraform
+				PreConfig: func() {
+					if err := testAccMimirClient(t).DeleteNamespace(context.Background(), "demo"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccResourceNamespace,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceNamespaceTenant(t *testing.T) {
+	// The Mimir instance used for acceptance tests has multitenancy disabled
+	server := newTestMimirServer(t, "/prometheus", "/alertmanager")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceNamespaceTenant, server.URL),
+				ConfigStateChecks: []statecheck.StateCheck{
+					SemanticYAMLStateCheck("mimirtool_ruler_namespace.tenant_a", "remote_config_yaml", testAccResourceNamespaceYaml),
+					SemanticYAMLStateCheck("mimirtool_ruler_namespace.tenant_b", "remote_config_yaml", testAccResourceNamespaceYamlAfterUpdate),
+					SemanticYAMLStateCheck("mimirtool_ruler_namespace.team_a", "remote_config_yaml", testAccResourceNamespaceYaml),
+				},
+			},
+			{
+				ResourceName:            "mimirtool_ruler_namespace.tenant_b",
+				ImportStateId:           "tenant_id=tenant-b:demo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"recording_rule_check", "strict_recording_rule_check", "config_yaml"},
+			},
+			{
+				// A namespace containing a slash is not read as tenant qualified
+				ResourceName:            "mimirtool_ruler_namespace.team_a",
+				ImportStateId:           "team/a",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"recording_rule_check", "strict_recording_rule_check", "config_yaml"},
+			},
+		},
+	})
+}
+
+func TestAccResourceNamespaceWaitForHealthyEvaluation(t *testing.T) {
+	defer func(interval time.Duration) { ruleEvaluationPollInterval = interval }(ruleEvaluationPollInterval)
+	ruleEvaluationPollInterval = 10 * time.Millisecond
+	server := newTestMimirServer(t, "/prometheus", "/alertmanager")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceNamespaceWaitForHealthyEvaluation, server.URL, "error", "sum by (job) (up)"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"mimirtool_ruler_namespace.health",
+						tfjsonpath.New("wait_for_healthy_evaluation").AtMapKey("severity"),
+						knownvalue.StringExact("error"),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccResourceNamespaceWaitForHealthyEvaluation, server.URL, "error", "up * on (instance) group_left (version) build_info"),
+				ExpectError: regexp.MustCompile(`(?s)Rule evaluation failed.*many-to-many matching not allowed`),
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceNamespaceWaitForHealthyEvaluation, server.URL, "warning", "up * on (instance) group_left (version) build_info"),
+			},
+			{
+				Config:      fmt.Sprintf(testAccResourceNamespaceWaitForHealthyEvaluation, server.URL, "fatal", "sum by (job) (up)"),
+				ExpectError: regexp.MustCompile("Invalid severity"),
+			},
+		},
+	})
+}
+
+func TestAccResourceNamespaceWaitForHealthyEvaluationCreate(t *testing.T) {
+	defer func(interval time.Duration) { ruleEvaluationPollInterval = interval }(ruleEvaluationPollInterval)
+	ruleEvaluationPollInterval = 10 * time.Millisecond
+	server := newTestMimirServer(t, "/prometheus", "/alertmanager")
+	config := fmt.Sprintf(testAccResourceNamespaceWaitForHealthyEvaluation, server.URL, "error", "up * on (instance) group_left (version) build_info")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// A failed evaluation is only a warning on create, the namespace is not tainted
+				Config: config,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"mimirtool_ruler_namespace.health",
+						tfjsonpath.New("namespace"),
+						knownvalue.StringExact("health"),
+					),
+				},
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				// Updates still fail with the error severity
+				Config:      fmt.Sprintf(testAccResourceNamespaceWaitForHealthyEvaluation, server.URL, "error", "up * on (job) group_left (version) build_info"),
+				ExpectError: regexp.MustCompile(`(?s)Rule evaluation failed.*many-to-many matching not allowed`),
+			},
+		},
+	})
+}
+
+func TestAccResourceNamespaceRulePolicy(t *testing.T) {
+	server := newTestMimirServer(t, "/prometheus", "/alertmanager")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccResourceNamespaceRulePolicy, server.URL, ""),
+				ExpectError: regexp.MustCompile(`(?s)Rule policy violation.*rule 0 \(alert "InstanceDown"\): the required annotation "runbook_url" is missing`),
+			},
+			{
+				// The resource policy overrides the required annotations of the provider one
+				Config: fmt.Sprintf(testAccResourceNamespaceRulePolicy, server.URL, `
+  rule_policy {
+    required_annotations = []
+  }`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"mimirtool_ruler_namespace.policy",
+						tfjsonpath.New("namespace"),
+						knownvalue.StringExact("policy"),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceNamespaceRulePolicy, server.URL, `
+  rule_policy {
+    alert_name_pattern = "[A-Z"
+  }`),
+				ExpectError: regexp.MustCompile("Invalid regular expression"),
+			},
+		},
+	})
+}
+
 func TestAccResourceNamespaceRename(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -122,12 +463,53 @@ func TestAccResourceNamespaceRename(t *testing.T) {
 	})
 }
 
+func TestRuleNamespaceYAMLSemanticEquals(t *testing.T) {
+	read := func(name string) RuleNamespaceYAMLValue {
+		content, err := os.ReadFile("testdata/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return NewRuleNamespaceYAMLValue(string(content))
+	}
+
+	for _, tc := range []struct {
+		a, b  RuleNamespaceYAMLValue
+		equal bool
+	}{
+		{read("rules.yaml"), read("rules2_spacing.yaml"), true},
+		{read("rules-quoting.yaml"), NewRuleNamespaceYAMLValue(testAccResourceNamespaceQuotingExpected), true},
+		{read("rules.yaml"), read("rules2.yaml"), false},
+		{read("rules.yaml"), NewRuleNamespaceYAMLValue("not: [valid"), false},
+	} {
+		equal, diags := tc.a.StringSemanticEquals(context.Background(), tc.b)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if equal != tc.equal {
+			t.Errorf("expected semantic equality to be %t between:\n%s\nand:\n%s", tc.equal, tc.a.ValueString(), tc.b.ValueString())
+		}
+	}
+}
+
 func TestAccResourceNamespaceDiffSuppress(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
+				Config: testAccResourceNamespace,
+			},
+			{
+				// Only the formatting changes, the stored rule groups are kept as is
 				Config: testAccResourceNamespaceWhitespaceDiff,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(
+							"mimirtool_ruler_namespace.demo",
+							tfjsonpath.New("remote_config_yaml"),
+							knownvalue.StringFunc(SemanticYAMLMatcher(testAccResourceNamespaceYaml)),
+						),
+					},
+				},
 				ConfigStateChecks: []statecheck.StateCheck{
 					statecheck.ExpectKnownValue(
 						"mimirtool_ruler_namespace.demo",
@@ -166,7 +548,7 @@ func TestAccResourceNamespaceCheckRules(t *testing.T) {
 		Steps: []resource.TestStep{
 			{
 				Config:      testAccResourceNamespaceFailsCheck,
-				ExpectError: regexp.MustCompile("namespace contains 1 rules that don't match the requirements"),
+				ExpectError: regexp.MustCompile(`group "mimir_api_1": rule 0 \(record "cluster_job_cortex_request_duration_seconds_99quantile"\): the name contains no colon`),
 			},
 		},
 	})
@@ -196,6 +578,86 @@ func TestAccResourceNamespaceNoCheck(t *testing.T) {
 	})
 }
 
+func TestAccResourceNamespaceGroups(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceNamespaceGroups,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_ruler_namespace.demo", "group.#", "2"),
+					resource.TestCheckResourceAttr("mimirtool_ruler_namespace.demo", "group.1.rule.0.labels.severity", "critical"),
+				),
+				ConfigStateChecks: []statecheck.StateCheck{
+					SemanticYAMLStateCheck("mimirtool_ruler_namespace.demo", "remote_config_yaml", testAccResourceNamespaceGroupsYaml),
+				},
+			},
+			{
+				// Moving the same rule groups to config_yaml does not change them in Mimir
+				Config: testAccResourceNamespaceGroupsAsYAML,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(
+							"mimirtool_ruler_namespace.demo",
+							tfjsonpath.New("remote_config_yaml"),
+							knownvalue.StringFunc(SemanticYAMLMatcher(testAccResourceNamespaceGroupsYaml)),
+						),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccResourceNamespaceGroupsValidation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceNamespaceGroupsConflict,
+				ExpectError: regexp.MustCompile("Conflicting namespace definitions"),
+			},
+			{
+				Config:      testAccResourceNamespaceGroupsMissing,
+				ExpectError: regexp.MustCompile("Missing namespace definition"),
+			},
+			{
+				Config:      testAccResourceNamespaceGroupsInvalid,
+				ExpectError: regexp.MustCompile(`group "mimir_alerts": rule 0: invalid for`),
+			},
+			{
+				Config:      testAccResourceNamespaceGroupsInvalidExpr,
+				ExpectError: regexp.MustCompile(`group "mimir_alerts": rule 1 \(alert "MimirRequestLatency"\): 1:\d+: parse error`),
+			},
+		},
+	})
+}
+
+func TestNamespaceYAMLValidatorExpressions(t *testing.T) {
+	content, err := os.ReadFile("testdata/rules-promql-error.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := validator.StringRequest{Path: path.Root("config_yaml"), ConfigValue: types.StringValue(string(content))}
+	resp := &validator.StringResponse{}
+	namespaceYAMLValidator{}.ValidateString(context.Background(), req, resp)
+
+	expected := []string{
+		`group "mimir_api_1": rule 0 (record "cluster_job:cortex_request_duration_seconds:99quantile"): 1:103: parse error: unexpected right parenthesis ')' (expr at line 4)`,
+		`group "mimir_api_2": rule 0 (alert "MimirRequestErrors"): 2:1: parse error: unexpected end of input (expr at line 11)`,
+	}
+	var details []string
+	for _, d := range resp.Diagnostics.Errors() {
+		if d.Summary() != "Invalid PromQL expression" {
+			t.Errorf("unexpected diagnostic %q", d.Summary())
+		}
+		details = append(details, d.Detail())
+	}
+	if !reflect.DeepEqual(details, expected) {
+		t.Fatalf("unexpected diagnostics\nExpected: %q\nActual: %q", expected, details)
+	}
+}
+
 func TestAccResourceNamespaceParseRules(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -204,10 +666,91 @@ func TestAccResourceNamespaceParseRules(t *testing.T) {
 				Config:      testAccResourceNamespaceParseError,
 				ExpectError: regexp.MustCompile("field expression not found"),
 			},
+			{
+				Config:      testAccResourceNamespacePromQLError,
+				ExpectError: regexp.MustCompile(`group "mimir_api_2": rule 0 \(alert "MimirRequestErrors"\): 2:1: parse error`),
+			},
 		},
 	})
 }
 
+const testAccResourceNamespaceTenant = `
+provider "mimirtool" {
+  address = %q
+}
+
+resource "mimirtool_ruler_namespace" "tenant_a" {
+	namespace = "demo"
+	tenant_id = "tenant-a"
+	config_yaml = file("testdata/rules.yaml")
+}
+
+resource "mimirtool_ruler_namespace" "tenant_b" {
+	namespace = "demo"
+	tenant_id = "tenant-b"
+	config_yaml = file("testdata/rules2.yaml")
+}
+
+resource "mimirtool_ruler_namespace" "team_a" {
+	namespace = "team/a"
+	config_yaml = file("testdata/rules.yaml")
+}
+`
+
+const testAccResourceNamespaceWaitForHealthyEvaluation = `
+provider "mimirtool" {
+  address = %[1]q
+}
+
+resource "mimirtool_ruler_namespace" "health" {
+  namespace   = "health"
+  config_yaml = <<EOT
+groups:
+  - name: joins
+    rules:
+      - record: instance:up:info
+        expr: %[3]s
+EOT
+
+  wait_for_healthy_evaluation {
+    timeout  = "10s"
+    severity = %[2]q
+  }
+}
+`
+
+const testAccResourceNamespaceRulePolicy = `
+provider "mimirtool" {
+  address = %q
+
+  rule_policy {
+    required_labels      = ["severity", "team"]
+    allowed_label_values = { severity = ["critical", "warning"] }
+    required_annotations = ["runbook_url"]
+    record_name_pattern  = "[a-z_]+:[a-z_]+:[a-z0-9_]+"
+    forbidden_functions  = ["label_replace"]
+  }
+}
+
+resource "mimirtool_ruler_namespace" "policy" {
+  namespace = "policy"
+
+  group {
+    name = "alerts"
+    rule {
+      alert  = "InstanceDown"
+      expr   = "up == 0"
+      labels = { severity = "critical", team = "infra" }
+    }
+    rule {
+      record = "job:up:sum"
+      expr   = "sum by (job) (up)"
+    }
+  }
+%s
+}
+`
+
 const testAccResourceNamespaceRename = `
 provider "mimirtool" {
   address = "http://localhost:8080"
@@ -282,6 +825,19 @@ const testAccResourceNamespaceYamlAfterUpdate = `groups:
           expr: histogram_quantile(0.99, sum by (le, cluster, job, route) (rate(cortex_request_duration_seconds_bucket[1m])))
 `
 
+const testAccResourceNamespaceYamlDrifted = `groups:
+    - name: mimir_api_1
+      rules:
+        - record: cluster_job:cortex_request_duration_seconds:99quantile
+          expr: histogram_quantile(0.99, sum by (le, cluster, job) (rate(cortex_request_duration_seconds_bucket[1m])))
+        - record: cluster_job:cortex_request_duration_seconds:50quantile
+          expr: histogram_quantile(0.5, sum by (le, cluster, job) (rate(cortex_request_duration_seconds_bucket[5m])))
+    - name: manual
+      rules:
+        - record: job:up:sum
+          expr: sum by (job) (up)
+`
+
 const testAccResourceNamespaceWhitespaceDiff = `provider "mimirtool" {
   address = "http://localhost:8080"
 }
@@ -330,6 +886,16 @@ resource "mimirtool_ruler_namespace" "demo" {
 	config_yaml = file("testdata/rules-parse-error.yaml")
   }
 `
+const testAccResourceNamespacePromQLError = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_ruler_namespace" "demo" {
+	namespace = "demo"
+	config_yaml = file("testdata/rules-promql-error.yaml")
+  }
+`
 const testAccResourceNamespaceQuoting = `
 provider "mimirtool" {
   address = "http://localhost:8080"
@@ -365,3 +931,144 @@ const testAccResourceNamespaceQuotingExpected = `groups:
                   LABELS = {{ $labels }}
             summary: Host high CPU load (instance {{ $labels.instance }})
 `
+
+const testAccResourceNamespaceGroups = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_ruler_namespace" "demo" {
+  namespace = "demo_groups"
+
+  group {
+    name = "mimir_api_1"
+    rule {
+      record = "cluster_job:cortex_request_duration_seconds:99quantile"
+      expr   = "histogram_quantile(0.99, sum(rate(cortex_request_duration_seconds_bucket[1m])) by (le, cluster, job))"
+    }
+  }
+
+  group {
+    name     = "mimir_alerts"
+    interval = "2m"
+    rule {
+      alert = "MimirRequestErrors"
+      expr  = "sum(rate(cortex_request_duration_seconds_count{status_code=~\"5..\"}[1m])) > 1"
+      for   = "15m"
+      labels = {
+        severity = "critical"
+      }
+      annotations = {
+        summary = "Mimir is returning errors."
+      }
+    }
+  }
+}
+`
+
+const testAccResourceNamespaceGroupsAsYAML = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_ruler_namespace" "demo" {
+  namespace   = "demo_groups"
+  config_yaml = <<EOT
+groups:
+  - name: mimir_api_1
+    rules:
+      - record: cluster_job:cortex_request_duration_seconds:99quantile
+        expr: histogram_quantile(0.99, sum(rate(cortex_request_duration_seconds_bucket[1m])) by (le, cluster, job))
+  - name: mimir_alerts
+    interval: 2m
+    rules:
+      - alert: MimirRequestErrors
+        expr: sum(rate(cortex_request_duration_seconds_count{status_code=~"5.."}[1m])) > 1
+        for: 15m
+        labels:
+          severity: critical
+        annotations:
+          summary: Mimir is returning errors.
+EOT
+}
+`
+
+const testAccResourceNamespaceGroupsYaml = `groups:
+    - name: mimir_api_1
+      rules:
+        - record: cluster_job:cortex_request_duration_seconds:99quantile
+          expr: histogram_quantile(0.99, sum by (le, cluster, job) (rate(cortex_request_duration_seconds_bucket[1m])))
+    - name: mimir_alerts
+      rules:
+        - expr: sum(rate(cortex_request_duration_seconds_count{status_code=~"5.."}[1m])) > 1
+`
+
+const testAccResourceNamespaceGroupsConflict = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_ruler_namespace" "demo" {
+  namespace   = "demo_groups"
+  config_yaml = file("testdata/rules.yaml")
+
+  group {
+    name = "mimir_api_1"
+    rule {
+      record = "cluster_job:cortex_request_duration_seconds:99quantile"
+      expr   = "histogram_quantile(0.99, sum(rate(cortex_request_duration_seconds_bucket[1m])) by (le, cluster, job))"
+    }
+  }
+}
+`
+
+const testAccResourceNamespaceGroupsMissing = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_ruler_namespace" "demo" {
+  namespace = "demo_groups"
+}
+`
+
+const testAccResourceNamespaceGroupsInvalid = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_ruler_namespace" "demo" {
+  namespace = "demo_groups"
+
+  group {
+    name = "mimir_alerts"
+    rule {
+      alert = "MimirRequestErrors"
+      expr  = "sum(rate(cortex_request_duration_seconds_count[1m])) > 1"
+      for   = "15 minutes"
+    }
+  }
+}
+`
+
+const testAccResourceNamespaceGroupsInvalidExpr = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_ruler_namespace" "demo" {
+  namespace = "demo_groups"
+
+  group {
+    name = "mimir_alerts"
+    rule {
+      alert = "MimirRequestErrors"
+      expr  = "sum(rate(cortex_request_duration_seconds_count[1m])) > 1"
+    }
+    rule {
+      alert = "MimirRequestLatency"
+      expr  = "histogram_quantile(0.99, sum by (le) (rate(cortex_request_duration_seconds_bucket[1m]))) >"
+    }
+  }
+}
+`
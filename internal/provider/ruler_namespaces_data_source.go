@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RulerNamespacesDataSource{}
+
+func NewRulerNamespacesDataSource() datasource.DataSource {
+	return &RulerNamespacesDataSource{}
+}
+
+// RulerNamespacesDataSource lists the namespaces and rule groups of a tenant.
+type RulerNamespacesDataSource struct {
+	client mimirClientInterface
+}
+
+// RulerNamespacesDataSourceModel describes the data source data model.
+type RulerNamespacesDataSourceModel struct {
+	ID             types.String            `tfsdk:"id"`
+	TenantID       types.String            `tfsdk:"tenant_id"`
+	NamespaceRegex types.String            `tfsdk:"namespace_regex"`
+	GroupRegex     types.String            `tfsdk:"group_regex"`
+	Namespaces     []namespaceSummaryModel `tfsdk:"namespaces"`
+}
+
+type namespaceSummaryModel struct {
+	Name   types.String            `tfsdk:"name"`
+	Groups []ruleGroupSummaryModel `tfsdk:"groups"`
+}
+
+type ruleGroupSummaryModel struct {
+	Name       types.String `tfsdk:"name"`
+	RulesCount types.Int64  `tfsdk:"rules_count"`
+}
+
+func (d *RulerNamespacesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ruler_namespaces"
+}
+
+func (d *RulerNamespacesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the namespaces and rule groups stored in Grafana Mimir for a tenant. [Official documentation](https://grafana.com/docs/mimir/latest/references/http-api/#list-rule-groups)",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "hash",
+			},
+			"tenant_id": schema.StringAttribute{
+				MarkdownDescription: "Tenant ID to list the namespaces of. Overrides the provider `tenant_id`.",
+				Optional:            true,
+			},
+			"namespace_regex": schema.StringAttribute{
+				MarkdownDescription: "Only list the namespaces whose name matches this regular expression (RE2 syntax, unanchored).",
+				Optional:            true,
+			},
+			"group_regex": schema.StringAttribute{
+				MarkdownDescription: "Only list the rule groups whose name matches this regular expression (RE2 syntax, unanchored). Namespaces without any matching group are left out.",
+				Optional:            true,
+			},
+			"namespaces": schema.ListNestedAttribute{
+				MarkdownDescription: "The namespaces of the tenant, sorted by name.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the namespace.",
+							Computed:            true,
+						},
+						"groups": schema.ListNestedAttribute{
+							MarkdownDescription: "The rule groups of the namespace, in the order they are stored in.",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"name": schema.StringAttribute{
+										MarkdownDescription: "The name of the rule group.",
+										Computed:            true,
+									},
+									"rules_count": schema.Int64Attribute{
+										MarkdownDescription: "The number of rules of the group.",
+										Computed:            true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RulerNamespacesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(mimirClientInterface)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected mimirClientInterface, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+func (d *RulerNamespacesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RulerNamespacesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespaceRegex := compileRegexAttribute(path.Root("namespace_regex"), data.NamespaceRegex, &resp.Diagnostics)
+	groupRegex := compileRegexAttribute(path.Root("group_regex"), data.GroupRegex, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ruleSet, err := tenantClient(d.client, data.TenantID).ListRules(ctx, "")
+	// Mimir answers 404 when the tenant has no rule group at all
+	if err != nil && !errors.Is(err, client.ErrResourceNotFound) {
+		resp.Diagnostics.AddError(
+			"Error Reading Mimir RuleGroups",
+			fmt.Sprintf("Could not list Mimir rule groups: %s", err.Error()),
+		)
+		return
+	}
+
+	names := make([]string, 0, len(ruleSet))
+	for name := range ruleSet {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	data.Namespaces = make([]namespaceSummaryModel, 0, len(names))
+	for _, name := range names {
+		if namespaceRegex != nil && !namespaceRegex.MatchString(name) {
+			continue
+		}
+		namespace := namespaceSummaryModel{
+			Name:   types.StringValue(name),
+			Groups: []ruleGroupSummaryModel{},
+		}
+		for _, group := range ruleSet[name] {
+			if groupRegex != nil && !groupRegex.MatchString(group.Name) {
+				continue
+			}
+			namespace.Groups = append(namespace.Groups, ruleGroupSummaryModel{
+				Name:       types.StringValue(group.Name),
+				RulesCount: types.Int64Value(int64(len(group.Rules))),
+			})
+		}
+		if len(namespace.Groups) == 0 {
+			continue
+		}
+		data.Namespaces = append(data.Namespaces, namespace)
+	}
+
+	data.ID = types.StringValue(hash(strings.Join([]string{data.TenantID.ValueString(), data.NamespaceRegex.ValueString(), data.GroupRegex.ValueString()}, "/")))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// compileRegexAttribute compiles the regular expression held by the attribute,
+// it returns nil when the attribute is not set.
+func compileRegexAttribute(attributePath path.Path, value types.String, diagnostics *diag.Diagnostics) *regexp.Regexp {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	re, err := regexp.Compile(value.ValueString())
+	if err != nil {
+		diagnostics.AddAttributeError(
+			attributePath,
+			"Invalid regular expression",
+			err.Error(),
+		)
+		return nil
+	}
+	return re
+}
@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceNamespaces(t *testing.T) {
+	server := newTestMimirServer(t, "/prometheus", "/alertmanager")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceNamespaces, server.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.all", "namespaces.#", "2"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.all", "namespaces.0.name", "team_a"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.all", "namespaces.0.groups.#", "1"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.all", "namespaces.0.groups.0.name", "mimir_api_1"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.all", "namespaces.0.groups.0.rules_count", "2"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.all", "namespaces.1.name", "team_b"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.all", "namespaces.1.groups.#", "2"),
+
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.team_b", "namespaces.#", "1"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.team_b", "namespaces.0.name", "team_b"),
+
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.api_2", "namespaces.#", "1"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.api_2", "namespaces.0.name", "team_b"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.api_2", "namespaces.0.groups.#", "1"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.api_2", "namespaces.0.groups.0.name", "mimir_api_2"),
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.api_2", "namespaces.0.groups.0.rules_count", "1"),
+
+					resource.TestCheckResourceAttr("data.mimirtool_ruler_namespaces.other_tenant", "namespaces.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceNamespacesInvalidRegex(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceNamespacesInvalidRegex,
+				ExpectError: regexp.MustCompile("Invalid regular expression"),
+			},
+		},
+	})
+}
+
+const testAccDataSourceNamespaces = `
+provider "mimirtool" {
+  address = %q
+}
+
+resource "mimirtool_ruler_namespace" "team_a" {
+  namespace   = "team_a"
+  config_yaml = file("testdata/rules.yaml")
+}
+
+resource "mimirtool_ruler_namespace" "team_b" {
+  namespace   = "team_b"
+  config_yaml = file("testdata/rules2.yaml")
+}
+
+data "mimirtool_ruler_namespaces" "all" {
+  depends_on = [mimirtool_ruler_namespace.team_a, mimirtool_ruler_namespace.team_b]
+}
+
+data "mimirtool_ruler_namespaces" "team_b" {
+  namespace_regex = "_b$"
+
+  depends_on = [mimirtool_ruler_namespace.team_a, mimirtool_ruler_namespace.team_b]
+}
+
+data "mimirtool_ruler_namespaces" "api_2" {
+  group_regex = "api_2"
+
+  depends_on = [mimirtool_ruler_namespace.team_a, mimirtool_ruler_namespace.team_b]
+}
+
+data "mimirtool_ruler_namespaces" "other_tenant" {
+  tenant_id = "tenant-a"
+
+  depends_on = [mimirtool_ruler_namespace.team_a, mimirtool_ruler_namespace.team_b]
+}
+`
+
+const testAccDataSourceNamespacesInvalidRegex = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+data "mimirtool_ruler_namespaces" "all" {
+  namespace_regex = "team_("
+}
+`
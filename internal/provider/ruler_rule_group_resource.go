@@ -0,0 +1,497 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                   = &RulerRuleGroupResource{}
+	_ resource.ResourceWithImportState    = &RulerRuleGroupResource{}
+	_ resource.ResourceWithValidateConfig = &RulerRuleGroupResource{}
+	_ resource.ResourceWithModifyPlan     = &RulerRuleGroupResource{}
+)
+
+func NewRulerRuleGroupResource() resource.Resource {
+	return &RulerRuleGroupResource{}
+}
+
+// RulerRuleGroupResource defines the resource implementation. Unlike the
+// namespace resource, it only manages a single group of the namespace and
+// leaves the other groups untouched.
+type RulerRuleGroupResource struct {
+	client mimirClientInterface
+	// rulePolicy is the policy of the provider, if any
+	rulePolicy *rulePolicyModel
+}
+
+// RulerRuleGroupResourceModel describes the resource data model.
+type RulerRuleGroupResourceModel struct {
+	ID                       types.String     `tfsdk:"id"`
+	Namespace                types.String     `tfsdk:"namespace"`
+	Name                     types.String     `tfsdk:"name"`
+	Interval                 types.String     `tfsdk:"interval"`
+	QueryOffset              types.String     `tfsdk:"query_offset"`
+	Limit                    types.Int64      `tfsdk:"limit"`
+	SourceTenants            types.List       `tfsdk:"source_tenants"`
+	Rules                    types.List       `tfsdk:"rule"`
+	StrictRecordingRuleCheck types.Bool       `tfsdk:"strict_recording_rule_check"`
+	RecordingRuleCheck       types.Bool       `tfsdk:"recording_rule_check"`
+	TenantID                 types.String     `tfsdk:"tenant_id"`
+	RulePolicy               *rulePolicyModel `tfsdk:"rule_policy"`
+}
+
+func (r *RulerRuleGroupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ruler_rule_group"
+}
+
+func (r *RulerRuleGroupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := ruleGroupAttributes()
+	// Renaming the group creates a new group in Mimir
+	attributes["name"] = schema.StringAttribute{
+		MarkdownDescription: "The name of the rule group, unique within the namespace.",
+		Required:            true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+	attributes["id"] = schema.StringAttribute{
+		Computed:            true,
+		MarkdownDescription: "hash",
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+	attributes["namespace"] = schema.StringAttribute{
+		MarkdownDescription: "The name of the namespace holding the rule group. The namespace may hold groups managed by other means.",
+		Required:            true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+	attributes["strict_recording_rule_check"] = schema.BoolAttribute{
+		MarkdownDescription: "Fails rules checks that do not match best practices exactly: recording rule names must have the three `level:metric:operations` parts, and a level missing the aggregation labels or operations not naming an operation of the expression are errors instead of warnings. See: https://prometheus.io/docs/practices/rules/",
+		Optional:            true,
+		Default:             booldefault.StaticBool(false),
+		Computed:            true,
+	}
+	attributes["recording_rule_check"] = schema.BoolAttribute{
+		MarkdownDescription: "Controls whether to run recording rule checks entirely. Recording rule names without colon fail the checks.",
+		Optional:            true,
+		Default:             booldefault.StaticBool(true),
+		Computed:            true,
+	}
+	attributes["tenant_id"] = schema.StringAttribute{
+		MarkdownDescription: "Tenant ID to manage the rule group in. Overrides the provider `tenant_id`.",
+		Optional:            true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a single rule group of a namespace, the other groups of the namespace are left untouched. " +
+			"Do not manage the namespace with `mimirtool_ruler_namespace` as well. " +
+			"[Official documentation](https://grafana.com/docs/mimir/latest/references/http-api/#set-rule-group)",
+		Attributes: attributes,
+		Blocks: map[string]schema.Block{
+			"rule":        ruleBlock(),
+			"rule_policy": rulePolicyResourceBlock(),
+		},
+	}
+}
+
+func (r *RulerRuleGroupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(mimirClientInterface)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected mimirClientInterface, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+	if data, ok := req.ProviderData.(*providerData); ok {
+		r.rulePolicy = data.rulePolicy
+	}
+}
+
+// ValidateConfig validates the rule group the same way as a namespace definition.
+func (r *RulerRuleGroupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config RulerRuleGroupResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.RulePolicy != nil && config.RulePolicy.isKnown(ctx) {
+		_, diags := config.RulePolicy.policy(ctx, path.Root("rule_policy"))
+		resp.Diagnostics.Append(diags...)
+	}
+
+	// Values may only be known at apply time
+	for _, value := range []interface{ IsUnknown() bool }{config.Name, config.Interval, config.QueryOffset, config.Limit} {
+		if value.IsUnknown() {
+			return
+		}
+	}
+	if !isFullyKnown(ctx, config.SourceTenants) || !isFullyKnown(ctx, config.Rules) {
+		return
+	}
+
+	group, diags := config.ruleGroup(ctx)
+	if diags.HasError() {
+		for _, d := range diags.Errors() {
+			resp.Diagnostics.AddAttributeError(path.Root("rule"), d.Summary(), d.Detail())
+		}
+		return
+	}
+	if exprErrs := ruleExpressionErrors([]rwrulefmt.RuleGroup{group}); len(exprErrs) > 0 {
+		for _, exprErr := range exprErrs {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rule").AtListIndex(exprErr.rule).AtName("expr"),
+				"Invalid PromQL expression",
+				exprErr.detail,
+			)
+		}
+		return
+	}
+	if _, err := ruleNamespaceFromGroups(ctx, []rwrulefmt.RuleGroup{group}); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("rule"),
+			"Invalid rule group",
+			fmt.Sprintf("Rule group definition is not valid: %s", err.Error()),
+		)
+	}
+}
+
+// ModifyPlan enforces the rule policy. The provider policy is only known once
+// the provider is configured, hence it is enforced here rather than in ValidateConfig.
+func (r *RulerRuleGroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan RulerRuleGroupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !isFullyKnown(ctx, plan.Rules) {
+		return
+	}
+	// Invalid definitions are reported by ValidateConfig
+	if group, diags := plan.ruleGroup(ctx); !diags.HasError() {
+		r.enforceRulePolicy(ctx, plan, group, &resp.Diagnostics)
+	}
+}
+
+func (r *RulerRuleGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan RulerRuleGroupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := plan.Namespace.ValueString()
+	name := plan.Name.ValueString()
+	cli := tenantClient(r.client, plan.TenantID)
+
+	tflog.Debug(ctx, "CREATE - values from plan", map[string]interface{}{
+		"tenant_id": plan.TenantID.ValueString(),
+		"namespace": namespace,
+		"group":     name,
+	})
+
+	group, ok := r.checkedRuleGroup(ctx, plan, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	// Refuse to take over a group created by someone else, it must be imported
+	_, err := cli.GetRuleGroup(ctx, namespace, name)
+	switch {
+	case err == nil:
+		resp.Diagnostics.AddError(
+			"Rule group already exists",
+			fmt.Sprintf("Rule group %q already exists in namespace %q, import it to manage it with Terraform.", name, namespace),
+		)
+		return
+	case !errors.Is(err, client.ErrResourceNotFound):
+		resp.Diagnostics.AddError(
+			"Failed to read existing rule group",
+			err.Error(),
+		)
+		return
+	}
+
+	if err := cli.CreateRuleGroup(ctx, namespace, group); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to create rule group",
+			fmt.Sprintf("Could not create rule group %q in namespace %q: %s", name, namespace, err.Error()),
+		)
+		return
+	}
+
+	plan.ID = rulerRuleGroupID(plan.TenantID, namespace, name)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *RulerRuleGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state RulerRuleGroupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := state.Namespace.ValueString()
+	name := state.Name.ValueString()
+
+	remote, err := tenantClient(r.client, state.TenantID).GetRuleGroup(ctx, namespace, name)
+	if err != nil {
+		if errors.Is(err, client.ErrResourceNotFound) {
+			resp.Diagnostics.AddWarning(
+				"Rule group not found",
+				fmt.Sprintf("Rule group %q no longer exists in namespace %q and will be re-created.", name, namespace),
+			)
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading Mimir RuleGroup",
+			fmt.Sprintf("Could not read Mimir rule group %q of namespace %q: %s", name, namespace, err.Error()),
+		)
+		return
+	}
+
+	// Only update the state when the group drifted, so that the user's
+	// formatting of the expressions is kept otherwise.
+	configured, diags := state.ruleGroup(ctx)
+	if diags.HasError() || !ruleGroupsEqual(configured, *remote) {
+		if !diags.HasError() {
+			drift := describeRuleGroupsDrift([]rwrulefmt.RuleGroup{*remote}, []rwrulefmt.RuleGroup{configured})
+			resp.Diagnostics.AddWarning(
+				"Rule group drifted from its configuration",
+				fmt.Sprintf("Rule group %q of namespace %q stored in Grafana Mimir differs from its configuration and will be updated:\n  - %s", name, namespace, strings.Join(drift, "\n  - ")),
+			)
+		}
+		resp.Diagnostics.Append(state.setRuleGroup(ctx, *remote)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	state.ID = rulerRuleGroupID(state.TenantID, namespace, name)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *RulerRuleGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan RulerRuleGroupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := plan.Namespace.ValueString()
+	group, ok := r.checkedRuleGroup(ctx, plan, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	// Posting a group replaces the group with the same name
+	if err := tenantClient(r.client, plan.TenantID).CreateRuleGroup(ctx, namespace, group); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to update rule group",
+			fmt.Sprintf("Could not update rule group %q in namespace %q: %s", group.Name, namespace, err.Error()),
+		)
+		return
+	}
+
+	plan.ID = rulerRuleGroupID(plan.TenantID, namespace, group.Name)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *RulerRuleGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state RulerRuleGroupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := tenantClient(r.client, state.TenantID).DeleteRuleGroup(ctx, state.Namespace.ValueString(), state.Name.ValueString())
+	if err != nil && !errors.Is(err, client.ErrResourceNotFound) {
+		resp.Diagnostics.AddError(
+			"Unable to Delete Resource",
+			"An unexpected error occurred while attempting to delete the resource. "+
+				"Please retry the operation or report this issue to the provider developers.\n\n"+
+				"HTTP Error: "+err.Error(),
+		)
+	}
+}
+
+func (r *RulerRuleGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The import ID is <namespace>/<group>, optionally prefixed by the tenant ID
+	tenantID, id := splitTenantImportID(req.ID)
+	if !strings.Contains(strings.Trim(id, "/"), "/") {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form [tenant_id=<tenant_id>:]<namespace>/<group>, got %q.", req.ID),
+		)
+		return
+	}
+
+	namespace, remote, err := findRuleGroupToImport(ctx, tenantClient(r.client, tenantID), id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Mimir RuleGroup after IMPORT",
+			fmt.Sprintf("Could not read Mimir rule group %q: %s", id, err.Error()),
+		)
+		return
+	}
+	name := id[len(namespace)+1:]
+
+	state := RulerRuleGroupResourceModel{
+		ID:                       rulerRuleGroupID(tenantID, namespace, name),
+		Namespace:                types.StringValue(namespace),
+		TenantID:                 tenantID,
+		StrictRecordingRuleCheck: types.BoolValue(false),
+		RecordingRuleCheck:       types.BoolValue(true),
+	}
+	resp.Diagnostics.Append(state.setRuleGroup(ctx, *remote)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// findRuleGroupToImport returns the namespace and the rule group identified by
+// <namespace>/<group>. As both names may contain slashes, the rule group is
+// looked up for every split of the ID, which must match exactly one of them.
+func findRuleGroupToImport(ctx context.Context, cli mimirClientInterface, id string) (string, *rwrulefmt.RuleGroup, error) {
+	var (
+		namespace string
+		found     *rwrulefmt.RuleGroup
+		matches   []string
+	)
+	for i, c := range id {
+		if c != '/' || i == 0 || i == len(id)-1 {
+			continue
+		}
+		rg, err := cli.GetRuleGroup(ctx, id[:i], id[i+1:])
+		if errors.Is(err, client.ErrResourceNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		namespace, found = id[:i], rg
+		matches = append(matches, fmt.Sprintf("group %q of namespace %q", id[i+1:], id[:i]))
+	}
+	switch len(matches) {
+	case 0:
+		return "", nil, fmt.Errorf("no rule group matches %q: %w", id, client.ErrResourceNotFound)
+	case 1:
+		return namespace, found, nil
+	default:
+		return "", nil, fmt.Errorf("%q matches several rule groups: %s", id, strings.Join(matches, ", "))
+	}
+}
+
+// checkedRuleGroup converts the planned rule group, runs the recording rule
+// checks on it and enforces the rule policy.
+func (r *RulerRuleGroupResource) checkedRuleGroup(ctx context.Context, plan RulerRuleGroupResourceModel, diagnostics *diag.Diagnostics) (rwrulefmt.RuleGroup, bool) {
+	group, diags := plan.ruleGroup(ctx)
+	diagnostics.Append(diags...)
+	if diagnostics.HasError() {
+		return group, false
+	}
+
+	ruleNamespace, err := ruleNamespaceFromGroups(ctx, []rwrulefmt.RuleGroup{group})
+	if err != nil {
+		diagnostics.AddError(
+			"Failed to parse rule group",
+			err.Error(),
+		)
+		return group, false
+	}
+	if plan.RecordingRuleCheck.ValueBool() {
+		diagnostics.Append(checkRecordingRules(ruleNamespace.Groups, plan.StrictRecordingRuleCheck.ValueBool())...)
+		if diagnostics.HasError() {
+			return group, false
+		}
+	}
+	r.enforceRulePolicy(ctx, plan, group, diagnostics)
+	if diagnostics.HasError() {
+		return group, false
+	}
+	return group, true
+}
+
+// enforceRulePolicy reports, on the rule blocks, every rule which does not
+// follow the provider policy overridden by the resource one.
+func (r *RulerRuleGroupResource) enforceRulePolicy(ctx context.Context, m RulerRuleGroupResourceModel, group rwrulefmt.RuleGroup, diagnostics *diag.Diagnostics) {
+	enforceRulePolicy(ctx, r.rulePolicy, m.RulePolicy, []rwrulefmt.RuleGroup{group}, func(violation rulePolicyViolation) path.Path {
+		return path.Root("rule").AtListIndex(violation.rule)
+	}, diagnostics)
+}
+
+// ruleGroup converts the model to a rule group.
+func (m RulerRuleGroupResourceModel) ruleGroup(ctx context.Context) (rwrulefmt.RuleGroup, diag.Diagnostics) {
+	groupModel := ruleGroupModel{
+		Name:          m.Name,
+		Interval:      m.Interval,
+		QueryOffset:   m.QueryOffset,
+		Limit:         m.Limit,
+		SourceTenants: m.SourceTenants,
+	}
+	diags := m.Rules.ElementsAs(ctx, &groupModel.Rules, false)
+	if diags.HasError() {
+		return rwrulefmt.RuleGroup{}, diags
+	}
+
+	group, err := ruleGroupFromModel(ctx, groupModel)
+	if err != nil {
+		diags.AddError("Invalid rule group", err.Error())
+	}
+	return group, diags
+}
+
+// setRuleGroup sets the model from a rule group read from Mimir.
+func (m *RulerRuleGroupResourceModel) setRuleGroup(ctx context.Context, group rwrulefmt.RuleGroup) diag.Diagnostics {
+	groupModel := ruleGroupModelFromRuleGroup(group)
+	rules, diags := types.ListValueFrom(ctx, ruleListType.ElemType, groupModel.Rules)
+	if diags.HasError() {
+		return diags
+	}
+
+	m.Name = groupModel.Name
+	m.Interval = groupModel.Interval
+	m.QueryOffset = groupModel.QueryOffset
+	m.Limit = groupModel.Limit
+	m.SourceTenants = groupModel.SourceTenants
+	m.Rules = rules
+	return diags
+}
+
+// rulerRuleGroupID returns the resource ID: the hash of the namespace and group
+// names, qualified with the tenant when the resource overrides the provider one.
+// They are separated by NUL bytes as the names may contain slashes.
+func rulerRuleGroupID(tenantID types.String, namespace, name string) types.String {
+	return types.StringValue(hash(strings.Join([]string{tenantID.ValueString(), namespace, name}, "\x00")))
+}
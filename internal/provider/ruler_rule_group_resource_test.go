@@ -0,0 +1,368 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/prometheus/prometheus/model/rulefmt"
+)
+
+func TestAccResourceRuleGroup(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRuleGroupsDestroyed(t, "demo_rule_groups", "team_a", "team_b"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceRuleGroup,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_ruler_rule_group.team_a", "name", "team_a"),
+					resource.TestCheckResourceAttr("mimirtool_ruler_rule_group.team_a", "interval", "1m"),
+					resource.TestCheckResourceAttr("mimirtool_ruler_rule_group.team_b", "rule.0.labels.severity", "critical"),
+					testAccCheckRuleGroupNames(t, "demo_rule_groups", "team_a", "team_b"),
+				),
+			},
+			{
+				// Updating a group leaves the other group of the namespace untouched
+				Config: testAccResourceRuleGroupAfterUpdate,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mimirtool_ruler_rule_group.team_a", "rule.#", "2"),
+					resource.TestCheckResourceAttr("mimirtool_ruler_rule_group.team_b", "rule.0.for", "15m"),
+					testAccCheckRuleGroupNames(t, "demo_rule_groups", "team_a", "team_b"),
+				),
+			},
+			{
+				ResourceName:            "mimirtool_ruler_rule_group.team_b",
+				ImportStateId:           "demo_rule_groups/team_b",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"recording_rule_check", "strict_recording_rule_check"},
+			},
+			{
+				// Changes made outside of Terraform to a managed group are detected
+				PreConfig: func() {
+					err := testAccMimirClient(t).CreateRuleGroup(context.Background(), "demo_rule_groups", rwrulefmt.RuleGroup{
+						RuleGroup: rulefmt.RuleGroup{Name: "team_a", Rules: testAccRuleGroupDriftedRules()},
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccResourceRuleGroupAfterUpdate,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config:      testAccResourceRuleGroupDuplicate,
+				ExpectError: regexp.MustCompile("Rule group already exists"),
+			},
+		},
+	})
+}
+
+func TestAccResourceRuleGroupValidation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceRuleGroupInvalid,
+				ExpectError: regexp.MustCompile("Invalid rule group"),
+			},
+		},
+	})
+}
+
+func TestAccResourceRuleGroupTenant(t *testing.T) {
+	server := newTestMimirServer(t, "/prometheus", "/alertmanager")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceRuleGroupTenant, server.URL),
+				Check:  resource.TestCheckResourceAttr("mimirtool_ruler_rule_group.demo", "tenant_id", "tenant-a"),
+			},
+			{
+				ResourceName:            "mimirtool_ruler_rule_group.demo",
+				ImportStateId:           "tenant_id=tenant-a:demo/demo_group",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"recording_rule_check", "strict_recording_rule_check"},
+			},
+			{
+				// A namespace containing a slash is not read as tenant qualified
+				ResourceName:            "mimirtool_ruler_rule_group.team_a",
+				ImportStateId:           "team/a/team_a_group",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"recording_rule_check", "strict_recording_rule_check"},
+			},
+		},
+	})
+}
+
+func TestAccResourceRuleGroupRulePolicy(t *testing.T) {
+	server := newTestMimirServer(t, "/prometheus", "/alertmanager")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The provider policy applies to rule groups as well
+				Config:      fmt.Sprintf(testAccResourceRuleGroupRulePolicy, server.URL, ""),
+				ExpectError: regexp.MustCompile(`(?s)Rule policy violation.*rule 0 \(alert "InstanceDown"\): the required annotation "runbook_url" is missing`),
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceRuleGroupRulePolicy, server.URL, `
+  rule_policy {
+    required_annotations = []
+  }`),
+				Check: resource.TestCheckResourceAttr("mimirtool_ruler_rule_group.policy", "rule.#", "1"),
+			},
+		},
+	})
+}
+
+func TestRulerRuleGroupID(t *testing.T) {
+	ids := map[string][3]string{}
+	for _, tc := range [][3]string{
+		{"", "a", "b/c"},
+		{"", "a/b", "c"},
+		{"a", "b", "c"},
+		{"tenant-a", "team/a", "g"},
+		{"tenant-a", "team", "a/g"},
+	} {
+		id := rulerRuleGroupID(types.StringValue(tc[0]), tc[1], tc[2]).ValueString()
+		if other, ok := ids[id]; ok {
+			t.Errorf("rule groups %q and %q have the same ID", tc, other)
+		}
+		ids[id] = tc
+	}
+}
+
+func TestFindRuleGroupToImport(t *testing.T) {
+	ctx := context.Background()
+	cli := newFakeMimirClient()
+	for namespace, name := range map[string]string{"team/a": "g", "a": "b/c", "a/b": "c"} {
+		if err := cli.CreateRuleGroup(ctx, namespace, rwrulefmt.RuleGroup{RuleGroup: rulefmt.RuleGroup{Name: name}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	namespace, rg, err := findRuleGroupToImport(ctx, cli, "team/a/g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if namespace != "team/a" || rg.Name != "g" {
+		t.Fatalf("expected group \"g\" of namespace \"team/a\", got group %q of namespace %q", rg.Name, namespace)
+	}
+
+	if _, _, err := findRuleGroupToImport(ctx, cli, "team/b/g"); !errors.Is(err, client.ErrResourceNotFound) {
+		t.Fatalf("expected ErrResourceNotFound, got %v", err)
+	}
+	if _, _, err := findRuleGroupToImport(ctx, cli, "a/b/c"); err == nil || !strings.Contains(err.Error(), "matches several rule groups") {
+		t.Fatalf("expected an ambiguous import ID error, got %v", err)
+	}
+}
+
+func testAccRuleGroupDriftedRules() []rulefmt.RuleNode {
+	var rules []rulefmt.RuleNode
+	for _, record := range []string{"job:up:sum", "job:up:count"} {
+		rules = append(rules, rulefmt.RuleNode{
+			Record: stringNode(record),
+			Expr:   stringNode("sum by (job) (up)"),
+		})
+	}
+	return rules
+}
+
+// testAccCheckRuleGroupNames checks the groups stored in Mimir for the namespace.
+func testAccCheckRuleGroupNames(t *testing.T, namespace string, names ...string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		groups, err := listRuleGroups(context.Background(), testAccMimirClient(t), namespace)
+		if err != nil {
+			return err
+		}
+		if len(groups) != len(names) {
+			return fmt.Errorf("expected %d groups in namespace %q, got %d", len(names), namespace, len(groups))
+		}
+		for i, group := range groups {
+			if group.Name != names[i] {
+				return fmt.Errorf("expected group %d of namespace %q to be %q, got %q", i, namespace, names[i], group.Name)
+			}
+		}
+		return nil
+	}
+}
+
+// testAccCheckRuleGroupsDestroyed checks the groups have been deleted from Mimir.
+func testAccCheckRuleGroupsDestroyed(t *testing.T, namespace string, names ...string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		for _, name := range names {
+			if _, err := testAccMimirClient(t).GetRuleGroup(context.Background(), namespace, name); err == nil {
+				return fmt.Errorf("rule group %q of namespace %q still exists", name, namespace)
+			}
+		}
+		return nil
+	}
+}
+
+const testAccResourceRuleGroup = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_ruler_rule_group" "team_a" {
+  namespace = "demo_rule_groups"
+  name      = "team_a"
+  interval  = "1m"
+
+  rule {
+    record = "job:up:sum"
+    expr   = "sum by (job) (up)"
+  }
+}
+
+resource "mimirtool_ruler_rule_group" "team_b" {
+  namespace = mimirtool_ruler_rule_group.team_a.namespace
+  name      = "team_b"
+
+  rule {
+    alert = "InstanceDown"
+    expr  = "up == 0"
+    labels = {
+      severity = "critical"
+    }
+  }
+}
+`
+
+const testAccResourceRuleGroupAfterUpdate = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_ruler_rule_group" "team_a" {
+  namespace = "demo_rule_groups"
+  name      = "team_a"
+  interval  = "1m"
+
+  rule {
+    record = "job:up:sum"
+    expr   = "sum by (job) (up)"
+  }
+
+  rule {
+    record = "job:up:count"
+    expr   = "count by (job) (up)"
+  }
+}
+
+resource "mimirtool_ruler_rule_group" "team_b" {
+  namespace = mimirtool_ruler_rule_group.team_a.namespace
+  name      = "team_b"
+
+  rule {
+    alert = "InstanceDown"
+    expr  = "up == 0"
+    for   = "15m"
+    labels = {
+      severity = "critical"
+    }
+    annotations = {
+      summary = "{{ $labels.instance }} is down."
+    }
+  }
+}
+`
+
+const testAccResourceRuleGroupDuplicate = testAccResourceRuleGroupAfterUpdate + `
+resource "mimirtool_ruler_rule_group" "duplicate" {
+  namespace = "demo_rule_groups"
+  name      = "team_b"
+
+  rule {
+    alert = "InstanceDown"
+    expr  = "up == 0"
+  }
+
+  depends_on = [mimirtool_ruler_rule_group.team_b]
+}
+`
+
+const testAccResourceRuleGroupInvalid = `
+provider "mimirtool" {
+  address = "http://localhost:8080"
+}
+
+resource "mimirtool_ruler_rule_group" "demo" {
+  namespace = "demo_rule_groups"
+  name      = "invalid"
+
+  rule {
+    alert = "InstanceDown"
+    expr  = "up =="
+  }
+}
+`
+
+const testAccResourceRuleGroupTenant = `
+provider "mimirtool" {
+  address = %q
+}
+
+resource "mimirtool_ruler_rule_group" "demo" {
+  namespace = "demo"
+  name      = "demo_group"
+  tenant_id = "tenant-a"
+
+  rule {
+    record = "job:up:sum"
+    expr   = "sum by (job) (up)"
+  }
+}
+
+resource "mimirtool_ruler_rule_group" "team_a" {
+  namespace = "team/a"
+  name      = "team_a_group"
+
+  rule {
+    record = "job:up:sum"
+    expr   = "sum by (job) (up)"
+  }
+}
+`
+
+const testAccResourceRuleGroupRulePolicy = `
+provider "mimirtool" {
+  address = %q
+
+  rule_policy {
+    required_labels      = ["severity"]
+    required_annotations = ["runbook_url"]
+  }
+}
+
+resource "mimirtool_ruler_rule_group" "policy" {
+  namespace = "policy"
+  name      = "alerts"
+
+  rule {
+    alert  = "InstanceDown"
+    expr   = "up == 0"
+    labels = { severity = "critical" }
+  }
+%s
+}
+`
@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RulesStatusDataSource{}
+
+func NewRulesStatusDataSource() datasource.DataSource {
+	return &RulesStatusDataSource{}
+}
+
+// RulesStatusDataSource reads how the ruler evaluates the rules of a namespace.
+type RulesStatusDataSource struct {
+	client mimirClientInterface
+}
+
+// RulesStatusDataSourceModel describes the data source data model.
+type RulesStatusDataSourceModel struct {
+	ID        types.String           `tfsdk:"id"`
+	TenantID  types.String           `tfsdk:"tenant_id"`
+	Namespace types.String           `tfsdk:"namespace"`
+	Healthy   types.Bool             `tfsdk:"healthy"`
+	Groups    []ruleGroupStatusModel `tfsdk:"groups"`
+}
+
+type ruleGroupStatusModel struct {
+	Name           types.String      `tfsdk:"name"`
+	LastEvaluation types.String      `tfsdk:"last_evaluation"`
+	EvaluationTime types.Float64     `tfsdk:"evaluation_time"`
+	Rules          []ruleStatusModel `tfsdk:"rules"`
+}
+
+type ruleStatusModel struct {
+	Name           types.String  `tfsdk:"name"`
+	Type           types.String  `tfsdk:"type"`
+	State          types.String  `tfsdk:"state"`
+	Health         types.String  `tfsdk:"health"`
+	LastError      types.String  `tfsdk:"last_error"`
+	LastEvaluation types.String  `tfsdk:"last_evaluation"`
+	EvaluationTime types.Float64 `tfsdk:"evaluation_time"`
+}
+
+func (d *RulesStatusDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rules_status"
+}
+
+func (d *RulesStatusDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads how the ruler evaluates the rules of a namespace, to check that rules which load fine do not fail their evaluations. " +
+			"[Official documentation](https://grafana.com/docs/mimir/latest/references/http-api/#list-rules)",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "hash",
+			},
+			"tenant_id": schema.StringAttribute{
+				MarkdownDescription: "Tenant ID to read the rules of. Overrides the provider `tenant_id`.",
+				Optional:            true,
+			},
+			"namespace": schema.StringAttribute{
+				MarkdownDescription: "The name of the namespace.",
+				Required:            true,
+			},
+			"healthy": schema.BoolAttribute{
+				MarkdownDescription: "Whether the ruler reports rule groups for the namespace and none of its rules failed its last evaluation. Rules which were not evaluated yet are healthy.",
+				Computed:            true,
+			},
+			"groups": schema.ListNestedAttribute{
+				MarkdownDescription: "The rule groups of the namespace, in the order they are stored in.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the rule group.",
+							Computed:            true,
+						},
+						"last_evaluation": schema.StringAttribute{
+							MarkdownDescription: "When the group was last evaluated, in RFC 3339 format. Not set when the group was not evaluated yet.",
+							Computed:            true,
+						},
+						"evaluation_time": schema.Float64Attribute{
+							MarkdownDescription: "How long the last evaluation of the group took, in seconds.",
+							Computed:            true,
+						},
+						"rules": schema.ListNestedAttribute{
+							MarkdownDescription: "The rules of the group, in their order.",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"name": schema.StringAttribute{
+										MarkdownDescription: "The name of the alert, or the name of the recorded metric.",
+										Computed:            true,
+									},
+									"type": schema.StringAttribute{
+										MarkdownDescription: "The type of the rule: `alerting` or `recording`.",
+										Computed:            true,
+									},
+									"state": schema.StringAttribute{
+										MarkdownDescription: "The state of an alerting rule: `inactive`, `pending` or `firing`. Not set for recording rules.",
+										Computed:            true,
+									},
+									"health": schema.StringAttribute{
+										MarkdownDescription: "The health of the rule: `ok`, `err` when its last evaluation failed, or `unknown` when it was not evaluated yet.",
+										Computed:            true,
+									},
+									"last_error": schema.StringAttribute{
+										MarkdownDescription: "The error of the last evaluation of the rule. Not set when the evaluation succeeded.",
+										Computed:            true,
+									},
+									"last_evaluation": schema.StringAttribute{
+										MarkdownDescription: "When the rule was last evaluated, in RFC 3339 format. Not set when the rule was not evaluated yet.",
+										Computed:            true,
+									},
+									"evaluation_time": schema.Float64Attribute{
+										MarkdownDescription: "How long the last evaluation of the rule took, in seconds.",
+										Computed:            true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RulesStatusDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(mimirClientInterface)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected mimirClientInterface, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+func (d *RulesStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RulesStatusDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := data.Namespace.ValueString()
+	groups, err := tenantClient(d.client, data.TenantID).ListRuleGroupStatuses(ctx, namespace)
+	if err != nil {
+		detail := fmt.Sprintf("Could not read the status of the rules of namespace %q: %s", namespace, err.Error())
+		if errors.Is(err, client.ErrResourceNotFound) {
+			detail += "\n\nThe ruler API was not found, check the provider `prometheus_http_prefix`."
+		}
+		resp.Diagnostics.AddError("Error Reading Mimir rules status", detail)
+		return
+	}
+
+	// A namespace without rule groups, e.g. not loaded by the ruler yet, is not healthy
+	data.Healthy = types.BoolValue(len(groups) > 0)
+	data.Groups = make([]ruleGroupStatusModel, 0, len(groups))
+	for _, group := range groups {
+		groupModel := ruleGroupStatusModel{
+			Name:           types.StringValue(group.Name),
+			LastEvaluation: evaluationTimeValue(group.LastEvaluation),
+			EvaluationTime: types.Float64Value(group.EvaluationTime),
+			Rules:          make([]ruleStatusModel, 0, len(group.Rules)),
+		}
+		for _, rule := range group.Rules {
+			if rule.Health == "err" {
+				data.Healthy = types.BoolValue(false)
+			}
+			groupModel.Rules = append(groupModel.Rules, ruleStatusModel{
+				Name:           types.StringValue(rule.Name),
+				Type:           types.StringValue(rule.Type),
+				State:          stringValueOrNull(rule.State),
+				Health:         types.StringValue(rule.Health),
+				LastError:      stringValueOrNull(rule.LastError),
+				LastEvaluation: evaluationTimeValue(rule.LastEvaluation),
+				EvaluationTime: types.Float64Value(rule.EvaluationTime),
+			})
+		}
+		data.Groups = append(data.Groups, groupModel)
+	}
+
+	data.ID = types.StringValue(hash(strings.Join([]string{data.TenantID.ValueString(), namespace}, "/")))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// evaluationTimeValue formats the time of an evaluation, the ruler reports the
+// zero time for rules which were not evaluated yet.
+func evaluationTimeValue(t time.Time) types.String {
+	if t.IsZero() {
+		return types.StringNull()
+	}
+	return types.StringValue(t.UTC().Format(time.RFC3339Nano))
+}
+
+func stringValueOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceRulesStatus(t *testing.T) {
+	server := newTestMimirServer(t, "/prometheus", "/alertmanager")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceRulesStatus, server.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.healthy", "healthy", "true"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.healthy", "groups.#", "1"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.healthy", "groups.0.name", "alerts"),
+					resource.TestCheckResourceAttrSet("data.mimirtool_rules_status.healthy", "groups.0.last_evaluation"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.healthy", "groups.0.evaluation_time", "0.002"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.healthy", "groups.0.rules.#", "2"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.healthy", "groups.0.rules.0.name", "InstanceDown"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.healthy", "groups.0.rules.0.type", "alerting"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.healthy", "groups.0.rules.0.state", "firing"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.healthy", "groups.0.rules.0.health", "ok"),
+					resource.TestCheckNoResourceAttr("data.mimirtool_rules_status.healthy", "groups.0.rules.0.last_error"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.healthy", "groups.0.rules.1.name", "job:up:sum"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.healthy", "groups.0.rules.1.type", "recording"),
+					resource.TestCheckNoResourceAttr("data.mimirtool_rules_status.healthy", "groups.0.rules.1.state"),
+
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.failing", "healthy", "false"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.failing", "groups.0.rules.0.health", "err"),
+					resource.TestCheckResourceAttrSet("data.mimirtool_rules_status.failing", "groups.0.rules.0.last_error"),
+
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.missing", "healthy", "false"),
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.missing", "groups.#", "0"),
+
+					resource.TestCheckResourceAttr("data.mimirtool_rules_status.other_tenant", "groups.#", "0"),
+				),
+			},
+			{
+				// A misrouted ruler API is not read as a namespace without rule groups
+				Config:      fmt.Sprintf(testAccDataSourceRulesStatusPrefix, server.URL),
+				ExpectError: regexp.MustCompile(`ruler API was not found, check the provider\s+` + "`prometheus_http_prefix`"),
+			},
+		},
+	})
+}
+
+const testAccDataSourceRulesStatus = `
+provider "mimirtool" {
+  address = %q
+}
+
+resource "mimirtool_ruler_namespace" "healthy" {
+  namespace   = "healthy"
+  config_yaml = <<EOT
+groups:
+  - name: alerts
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+      - record: job:up:sum
+        expr: sum by (job) (up)
+EOT
+}
+
+resource "mimirtool_ruler_namespace" "failing" {
+  namespace   = "failing"
+  config_yaml = <<EOT
+groups:
+  - name: joins
+    rules:
+      - record: instance:up:info
+        expr: up * on (instance) group_left (version) build_info
+EOT
+}
+
+data "mimirtool_rules_status" "healthy" {
+  namespace = mimirtool_ruler_namespace.healthy.namespace
+}
+
+data "mimirtool_rules_status" "failing" {
+  namespace = mimirtool_ruler_namespace.failing.namespace
+}
+
+data "mimirtool_rules_status" "missing" {
+  namespace = "missing"
+}
+
+data "mimirtool_rules_status" "other_tenant" {
+  tenant_id = "tenant-a"
+  namespace = mimirtool_ruler_namespace.healthy.namespace
+}
+`
+
+const testAccDataSourceRulesStatusPrefix = `
+provider "mimirtool" {
+  address                = %q
+  prometheus_http_prefix = "/custom/prometheus"
+}
+
+data "mimirtool_rules_status" "misrouted" {
+  namespace = "healthy"
+}
+`
@@ -2,6 +2,7 @@ package provider
 
 import (
 	context "context"
+	"time"
 
 	rwrulefmt "github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
 )
@@ -16,8 +17,94 @@ type mimirClientInterface interface {
 	ListRules(ctx context.Context, namespace string) (map[string][]rwrulefmt.RuleGroup, error)
 	DeleteNamespace(ctx context.Context, namespace string) error
 	CreateRuleGroup(ctx context.Context, namespace string, rg rwrulefmt.RuleGroup) error
+	GetRuleGroup(ctx context.Context, namespace string, groupName string) (*rwrulefmt.RuleGroup, error)
 	// Alertmanager
 	CreateAlertmanagerConfig(ctx context.Context, cfg string, templates map[string]string) error
 	GetAlertmanagerConfig(ctx context.Context) (string, map[string]string, error)
 	DeleteAlermanagerConfig(ctx context.Context) error
+	CreateSilence(ctx context.Context, silence alertmanagerSilence) (string, error)
+	GetSilence(ctx context.Context, id string) (*alertmanagerSilence, error)
+	ExpireSilence(ctx context.Context, id string) error
+	ListAlertmanagerAlerts(ctx context.Context) ([]alertmanagerAlert, error)
+	// Alerts and evaluation of the ruler
+	ListRulerAlerts(ctx context.Context) ([]rulerAlert, error)
+	ListRuleGroupStatuses(ctx context.Context, namespace string) ([]ruleGroupStatus, error)
+	// Tenant
+	WithTenantID(tenantID string) mimirClientInterface
+}
+
+// alertmanagerSilence is a silence of the Alertmanager v2 API.
+type alertmanagerSilence struct {
+	ID        string                       `json:"id,omitempty"`
+	Matchers  []alertmanagerSilenceMatcher `json:"matchers"`
+	StartsAt  time.Time                    `json:"startsAt"`
+	EndsAt    time.Time                    `json:"endsAt"`
+	CreatedBy string                       `json:"createdBy"`
+	Comment   string                       `json:"comment"`
+	Status    *alertmanagerSilenceStatus   `json:"status,omitempty"`
+}
+
+type alertmanagerSilenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+type alertmanagerSilenceStatus struct {
+	// State is one of active, pending or expired
+	State string `json:"state"`
+}
+
+// rulerAlert is an alert of the ruler Prometheus API.
+type rulerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	// State is one of pending or firing
+	State    string     `json:"state"`
+	ActiveAt *time.Time `json:"activeAt,omitempty"`
+	Value    string     `json:"value"`
+}
+
+// alertmanagerAlert is an alert of the Alertmanager v2 API.
+type alertmanagerAlert struct {
+	Labels       map[string]string       `json:"labels"`
+	Annotations  map[string]string       `json:"annotations"`
+	StartsAt     time.Time               `json:"startsAt"`
+	EndsAt       time.Time               `json:"endsAt"`
+	GeneratorURL string                  `json:"generatorURL,omitempty"`
+	Fingerprint  string                  `json:"fingerprint,omitempty"`
+	Status       alertmanagerAlertStatus `json:"status"`
+}
+
+type alertmanagerAlertStatus struct {
+	// State is one of unprocessed, active or suppressed
+	State       string   `json:"state"`
+	SilencedBy  []string `json:"silencedBy"`
+	InhibitedBy []string `json:"inhibitedBy"`
+}
+
+// ruleGroupStatus is the evaluation status of a rule group of the ruler
+// Prometheus API. The file of the group is its namespace.
+type ruleGroupStatus struct {
+	Name           string       `json:"name"`
+	File           string       `json:"file"`
+	Rules          []ruleStatus `json:"rules"`
+	Interval       float64      `json:"interval"`
+	LastEvaluation time.Time    `json:"lastEvaluation"`
+	EvaluationTime float64      `json:"evaluationTime"`
+}
+
+type ruleStatus struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+	// Type is one of alerting or recording
+	Type string `json:"type"`
+	// State is one of inactive, pending or firing, for alerting rules only
+	State string `json:"state,omitempty"`
+	// Health is one of ok, err or unknown
+	Health         string    `json:"health"`
+	LastError      string    `json:"lastError,omitempty"`
+	LastEvaluation time.Time `json:"lastEvaluation"`
+	EvaluationTime float64   `json:"evaluationTime"`
 }
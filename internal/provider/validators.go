@@ -2,9 +2,15 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/grafana/mimir/pkg/mimirtool/rules"
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/prometheus/promql/parser"
 	"gopkg.in/yaml.v3"
 )
 
@@ -27,6 +33,19 @@ func (v namespaceYAMLValidator) ValidateString(ctx context.Context, req validato
 		// Let the non-empty validator handle this case
 		return
 	}
+	// Report every invalid PromQL expression separately, as the namespace parser
+	// only returns them as a single error
+	var ruleNamespace rules.RuleNamespace
+	decoder := yaml.NewDecoder(strings.NewReader(req.ConfigValue.ValueString()))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&ruleNamespace); err == nil {
+		if exprErrs := ruleExpressionErrors(ruleNamespace.Groups); len(exprErrs) > 0 {
+			for _, exprErr := range exprErrs {
+				resp.Diagnostics.AddAttributeError(req.Path, "Invalid PromQL expression", exprErr.detail)
+			}
+			return
+		}
+	}
 	_, err := getRuleNamespaceFromYAML(ctx, req.ConfigValue.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddAttributeError(
@@ -37,6 +56,49 @@ func (v namespaceYAMLValidator) ValidateString(ctx context.Context, req validato
 	}
 }
 
+// ruleExpressionError describes a rule whose PromQL expression does not parse.
+type ruleExpressionError struct {
+	// group and rule are the indexes of the rule
+	group, rule int
+	detail      string
+}
+
+// ruleExpressionErrors parses the expression of every rule with the PromQL
+// parser. Each error names the group, the rule and the position of the error in
+// the expression, along with the line of the expression in the YAML definition
+// the rule was read from, if any.
+func ruleExpressionErrors(groups []rwrulefmt.RuleGroup) []ruleExpressionError {
+	var exprErrs []ruleExpressionError
+	for i, group := range groups {
+		for j, rule := range group.Rules {
+			// A missing expression is reported by the namespace parser
+			if rule.Expr.Value == "" {
+				continue
+			}
+			_, err := parser.ParseExpr(rule.Expr.Value)
+			if err == nil {
+				continue
+			}
+			messages := []string{err.Error()}
+			var parseErrs parser.ParseErrors
+			if errors.As(err, &parseErrs) && len(parseErrs) > 0 {
+				messages = messages[:0]
+				for _, parseErr := range parseErrs {
+					messages = append(messages, parseErr.Error())
+				}
+			}
+			for _, message := range messages {
+				detail := fmt.Sprintf("group %q: rule %d (%s): %s", group.Name, j, ruleDisplayName(rule), message)
+				if rule.Expr.Line > 0 {
+					detail += fmt.Sprintf(" (expr at line %d)", rule.Expr.Line)
+				}
+				exprErrs = append(exprErrs, ruleExpressionError{group: i, rule: j, detail: detail})
+			}
+		}
+	}
+	return exprErrs
+}
+
 // yamlSyntaxValidator checks that a string is valid YAML
 
 type yamlSyntaxValidator struct{}
@@ -63,3 +125,75 @@ func (v yamlSyntaxValidator) ValidateString(_ context.Context, req validator.Str
 		)
 	}
 }
+
+// alertmanagerConfigValidator loads the string with the upstream Alertmanager
+// configuration loader, the same checks as `mimirtool alertmanager verify`:
+// receiver references, route tree, inhibit rules, matchers, time intervals and durations.
+// Invalid YAML syntax is left to yamlSyntaxValidator.
+
+type alertmanagerConfigValidator struct{}
+
+func (v alertmanagerConfigValidator) Description(_ context.Context) string {
+	return "Ensures the string is a valid Alertmanager configuration"
+}
+
+func (v alertmanagerConfigValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v alertmanagerConfigValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() || req.ConfigValue.ValueString() == "" {
+		return
+	}
+	var temp interface{}
+	if err := yaml.Unmarshal([]byte(req.ConfigValue.ValueString()), &temp); err != nil {
+		return
+	}
+	if _, err := loadAlertmanagerConfig(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Alertmanager configuration",
+			fmt.Sprintf("Alertmanager configuration is not valid: %s", err.Error()),
+		)
+	}
+}
+
+// alertmanagerTemplatesValidator parses each template of the map with the
+// Alertmanager template engine and its default functions.
+
+type alertmanagerTemplatesValidator struct{}
+
+func (v alertmanagerTemplatesValidator) Description(_ context.Context) string {
+	return "Ensures each value is a valid Alertmanager notification template"
+}
+
+func (v alertmanagerTemplatesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v alertmanagerTemplatesValidator) ValidateMap(_ context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	for name, value := range mapStringFromTypesMap(req.ConfigValue) {
+		if err := parseAlertmanagerTemplate(value); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				req.Path.AtMapKey(name),
+				"Invalid Alertmanager template",
+				fmt.Sprintf("Template %q is not valid: %s", name, err.Error()),
+			)
+		}
+	}
+}
+
+func parseAlertmanagerTemplate(content string) error {
+	tmpl, err := template.New()
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Parse(strings.NewReader(content)); err != nil {
+		// Errors are reported as "template: <name>:<line>: ...", the template is unnamed here
+		return errors.New(strings.Replace(err.Error(), "template: :", "line ", 1))
+	}
+	return nil
+}